@@ -16,8 +16,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/cmd/export"
+	"github.com/tosbaa/acucli/cmd/report"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/notify"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/vulnformat"
 )
 
 // Target structure for adding a target
@@ -130,7 +137,6 @@ This command performs the following steps:
 
 // Add a target and return the target ID
 func addTarget(targetURL string) (string, error) {
-	fmt.Printf("Debug: Adding target with URL: %s\n", targetURL)
 	targets := []Target{
 		{
 			Address:     targetURL,
@@ -147,33 +153,26 @@ func addTarget(targetURL string) (string, error) {
 
 	requestJson, err := json.Marshal(postBody)
 	if err != nil {
-		fmt.Printf("Debug: Error marshaling target request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error marshaling target request: %v", err)
 	}
 
-	fmt.Printf("Debug: Making POST request to /targets/add with body: %s\n", string(requestJson))
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/targets/add"), bytes.NewBuffer(requestJson))
 	if err != nil {
-		fmt.Printf("Debug: Error creating target request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error creating target request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpclient.MyHTTPClient.Do(req)
 	if err != nil {
-		fmt.Printf("Debug: Error making target request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error making target request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("Debug: Error reading target response body: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error reading target response body: %v", err)
 	}
 
-	fmt.Printf("Debug: Target API response: %s\n", string(body))
-
 	var response struct {
 		Targets []struct {
 			TargetID string `json:"target_id"`
@@ -182,17 +181,15 @@ func addTarget(targetURL string) (string, error) {
 
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		fmt.Printf("Debug: Error parsing target response: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error parsing target response: %v", err)
 	}
 
 	if len(response.Targets) == 0 {
-		fmt.Println("Debug: No target ID in response")
-		return "", fmt.Errorf("no target ID in response")
+		return "", fmt.Errorf("no target ID in response, status code: %d", resp.StatusCode)
 	}
 
 	targetID := response.Targets[0].TargetID
-	fmt.Printf("Debug: Successfully added target with ID: %s\n", targetID)
+	resolver.Invalidate(resolver.KindTarget)
 	return targetID, nil
 }
 
@@ -218,8 +215,6 @@ func checkTargetExists(targetID string) (bool, error) {
 
 // Start a scan and return the scan ID
 func startScan(targetID, scanProfileID string) (string, error) {
-	fmt.Printf("Debug: Starting scan for target ID: %s with profile ID: %s\n", targetID, scanProfileID)
-
 	schedule := ScanSchedule{
 		Disable:       true,
 		TimeSensitive: false,
@@ -234,46 +229,36 @@ func startScan(targetID, scanProfileID string) (string, error) {
 
 	requestJson, err := json.Marshal(postBody)
 	if err != nil {
-		fmt.Printf("Debug: Error marshaling scan request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error marshaling scan request: %v", err)
 	}
 
-	fmt.Printf("Debug: Making POST request to /scans with body: %s\n", string(requestJson))
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/scans"), bytes.NewBuffer(requestJson))
 	if err != nil {
-		fmt.Printf("Debug: Error creating scan request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error creating scan request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpclient.MyHTTPClient.Do(req)
 	if err != nil {
-		fmt.Printf("Debug: Error making scan request: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error making scan request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("Debug: Error reading scan response body: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error reading scan response body: %v", err)
 	}
 
-	fmt.Printf("Debug: Scan API response: %s\n", string(body))
-
 	var response ScanResponse
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		fmt.Printf("Debug: Error parsing scan response: %v\n", err)
-		return "", err
+		return "", fmt.Errorf("error parsing scan response: %v", err)
 	}
 
 	if response.ScanID == "" {
-		fmt.Println("Debug: No scan ID in response")
-		return "", fmt.Errorf("no scan ID in response")
+		return "", fmt.Errorf("no scan ID in response, status code: %d", resp.StatusCode)
 	}
 
-	fmt.Printf("Debug: Successfully started scan with ID: %s\n", response.ScanID)
 	return response.ScanID, nil
 }
 
@@ -299,55 +284,42 @@ func checkScanExists(scanID string) (bool, error) {
 
 // Wait for scan completion
 func waitForScanCompletion(scanID string, timeoutSeconds int) (bool, error) {
-	fmt.Printf("Debug: Waiting for scan completion. Scan ID: %s, Timeout: %d seconds\n", scanID, timeoutSeconds)
-
 	startTime := time.Now()
 	for {
 		if time.Since(startTime) > time.Duration(timeoutSeconds)*time.Second {
-			fmt.Printf("Debug: Scan wait timed out after %d seconds\n", timeoutSeconds)
 			return false, nil
 		}
 
 		req, err := http.NewRequest("GET", fmt.Sprintf("%s/scans/%s", viper.GetString("URL"), scanID), nil)
 		if err != nil {
-			fmt.Printf("Debug: Error creating scan status request: %v\n", err)
-			return false, err
+			return false, fmt.Errorf("error creating scan status request: %v", err)
 		}
 
 		resp, err := httpclient.MyHTTPClient.Do(req)
 		if err != nil {
-			fmt.Printf("Debug: Error making scan status request: %v\n", err)
-			return false, err
+			return false, fmt.Errorf("error making scan status request: %v", err)
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			fmt.Printf("Debug: Error reading scan status response: %v\n", err)
-			return false, err
+			return false, fmt.Errorf("error reading scan status response: %v", err)
 		}
 
-		fmt.Printf("Debug: Scan status API response: %s\n", string(body))
-
 		var response ScanResponse
 		err = json.Unmarshal(body, &response)
 		if err != nil {
-			fmt.Printf("Debug: Error parsing scan status response: %v\n", err)
-			return false, err
+			return false, fmt.Errorf("error parsing scan status response: %v", err)
 		}
 
 		status := response.CurrentSession.Status
-		fmt.Printf("Debug: Current scan status: %s\n", status)
 
 		if status == "completed" {
-			fmt.Println("Debug: Scan completed successfully")
 			return true, nil
 		} else if status == "failed" || status == "aborted" {
-			fmt.Printf("Debug: Scan failed or was aborted with status: %s\n", status)
 			return false, fmt.Errorf("scan failed with status: %s", status)
 		}
 
-		fmt.Println("Debug: Scan still in progress, waiting 10 seconds before next check")
 		time.Sleep(10 * time.Second)
 	}
 }
@@ -421,7 +393,7 @@ func checkReportExists(reportID string) (bool, error) {
 }
 
 // Wait for report completion and get download links
-func waitForReportCompletion(reportID string, timeoutSeconds int) ([]string, error) {
+func waitForReportCompletion(reportID string, timeoutSeconds int, outputFormat string) ([]string, error) {
 	startTime := time.Now()
 	timeout := time.Duration(timeoutSeconds) * time.Second
 
@@ -650,6 +622,7 @@ func removeTarget(targetID string) error {
 	if resp.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("error removing target, status code: %d", resp.StatusCode)
 	}
+	resolver.Invalidate(resolver.KindTarget)
 
 	return nil
 }
@@ -716,107 +689,129 @@ func checkExportExists(exportID string) (bool, error) {
 	return true, nil
 }
 
-// Wait for export completion and get download links
-func waitForExportCompletion(exportID string, timeoutSeconds int) ([]string, error) {
-	startTime := time.Now()
-	timeout := time.Duration(timeoutSeconds) * time.Second
-
-	for {
-		// Check if timeout has been reached
-		if time.Since(startTime) > timeout {
-			return nil, fmt.Errorf("timeout waiting for export completion")
-		}
-
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/exports/%s", viper.GetString("URL"), exportID), nil)
-		if err != nil {
-			return nil, fmt.Errorf("error creating request: %v", err)
-		}
-
-		resp, err := httpclient.MyHTTPClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("error making request: %v", err)
-		}
+// Write the vulnerabilities of the scan in a vulnerability management format. The output path is
+// a file when it has the format's extension, a directory otherwise.
+func writeFindings(scanID string, format string, outputPath string) (string, error) {
+	scan, result, vulnerabilities, err := scandata.LoadResult(scanID, "", true)
+	if err != nil {
+		return "", err
+	}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+	data, err := vulnformat.Encode(format, []vulnformat.ScanFindings{{
+		Address:         scan.Target.Address,
+		StartDate:       result.StartDate,
+		EndDate:         result.EndDate,
+		Vulnerabilities: vulnerabilities,
+	}})
+	if err != nil {
+		return "", err
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("error reading response body: %v", err)
-		}
+	path := outputPath
+	if !strings.HasSuffix(strings.ToLower(path), vulnformat.Extension(format)) {
+		path = filepath.Join(outputPath, fmt.Sprintf("acunetix-%s-%s%s", scanID, format, vulnformat.Extension(format)))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
 
-		var exportResponse map[string]interface{}
-		err = json.Unmarshal(body, &exportResponse)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing response: %v", err)
-		}
+// Options of an auto run. Runs only use their options, so several can run at the same time.
+type Options struct {
+	TargetURL        string
+	ScanProfileID    string
+	ReportTemplateID string
+	OutputFormat     string
+	OutputPath       string
+	WaitTimeout      int
+	FailOn           string
+	// Progress receives every step of the run, the steps are printed as JSON when it is nil
+	Progress func(step map[string]interface{})
+}
 
-		// Check if export is completed
-		if status, ok := exportResponse["status"].(string); ok && status == "completed" {
-			// Get download links
-			if download, ok := exportResponse["download"].([]interface{}); ok {
-				var links []string
-				for _, link := range download {
-					if linkStr, ok := link.(string); ok {
-						links = append(links, linkStr)
-					}
-				}
-				return links, nil
-			}
-			return nil, fmt.Errorf("no download links found in response")
-		}
+// Result of an auto run
+type Result struct {
+	TargetID   string                  `json:"target_id"`
+	ScanID     string                  `json:"scan_id"`
+	ReportID   string                  `json:"report_id,omitempty"`
+	Files      []string                `json:"files"`
+	Counts     scandata.SeverityCounts `json:"counts"`
+	GateFailed bool                    `json:"gate_failed"`
+}
 
-		// Wait for 5 seconds before checking again
-		time.Sleep(5 * time.Second)
+// RunAutoCommand executes the auto workflow with the given parameters
+func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, outputFormat string, templateID string, failOn string) error {
+	if templateID == "" {
+		templateID = reportTemplateID
+	}
+
+	result, err := Run(Options{
+		TargetURL:        targetURL,
+		ScanProfileID:    scanProfileID,
+		ReportTemplateID: templateID,
+		OutputFormat:     outputFormat,
+		OutputPath:       outputPath,
+		WaitTimeout:      waitTimeout,
+		FailOn:           failOn,
+	})
+	if err != nil {
+		return err
+	}
+	if result.GateFailed {
+		return fmt.Errorf("gate failed: vulnerabilities at or above %s found", failOn)
 	}
+	return nil
 }
 
-// Remove an export
-func removeExport(exportID string) error {
-	// Create JSON request
-	requestBody := map[string]interface{}{
-		"export_id_list": []string{exportID},
-	}
-	requestJson, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("error creating JSON request: %v", err)
+// Run executes the auto workflow: add the target, scan it, write the report, export or findings to
+// the output path and remove what was created on the server. A failed gate is reported in the result.
+func Run(opts Options) (Result, error) {
+	var result Result
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(step map[string]interface{}) { jsonoutput.OutputJSON(step) }
 	}
+	format := strings.ToLower(opts.OutputFormat)
+	outputPath := opts.OutputPath
+	waitTimeout := opts.WaitTimeout
 
-	// Create request
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/exports/delete"), bytes.NewBuffer(requestJson))
-	if err != nil {
-		return fmt.Errorf("error creating request: %v", err)
+	if opts.TargetURL == "" {
+		return result, fmt.Errorf("target URL is required")
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Perform request
-	resp, err := httpclient.MyHTTPClient.Do(req)
+	gate, err := notify.ParseGate(opts.FailOn)
 	if err != nil {
-		return fmt.Errorf("error making request: %v", err)
+		return result, fmt.Errorf("invalid --fail-on: %v", err)
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("error removing export, status code: %d", resp.StatusCode)
+	profileID := opts.ScanProfileID
+	if profileID == "" {
+		// Use default scan profile ID if not provided
+		profileID = "11111111-1111-1111-1111-111111111119" //  "Critical / High / Medium Risk",
 	}
 
-	return nil
-}
-
-// RunAutoCommand executes the auto workflow with the given parameters
-func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, outputFormat string) error {
-	if targetURL == "" {
-		return fmt.Errorf("target URL is required")
+	// Accept scan profile names as well as IDs
+	profileID, err = resolver.Resolve(resolver.KindScanProfile, profileID)
+	if err != nil {
+		return result, fmt.Errorf("failed to resolve scan profile: %v", err)
 	}
 
-	if scanProfileID == "" {
-		// Use default scan profile ID if not provided
-		scanProfileID = "11111111-1111-1111-1111-111111111119" //  "Critical / High / Medium Risk",
+	templateID := opts.ReportTemplateID
+	if templateID == "" && format != "csv" {
+		// Use default report template ID if not provided and format is not CSV
+		templateID = "11111111-1111-1111-1111-111111111126"
 	}
 
-	if reportTemplateID == "" && outputFormat != "csv" {
-		// Use default report template ID if not provided and format is not CSV
-		reportTemplateID = "11111111-1111-1111-1111-111111111126"
+	if templateID != "" {
+		// Accept template names as well as IDs
+		templateID, err = report.ResolveTemplateID(templateID)
+		if err != nil {
+			return result, fmt.Errorf("failed to resolve report template: %v", err)
+		}
 	}
 
 	// Create output directory if specified and doesn't exist
@@ -826,19 +821,19 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 		if outputDir != "." {
 			err := os.MkdirAll(outputDir, 0755)
 			if err != nil {
-				return fmt.Errorf("failed to create output directory: %v", err)
+				return result, fmt.Errorf("failed to create output directory: %v", err)
 			}
 		}
 	}
 
 	// Step 1: Add target
-	targetID, err := addTarget(targetURL)
+	targetID, err := addTarget(opts.TargetURL)
 	if err != nil {
-		return fmt.Errorf("failed to add target: %v", err)
+		return result, fmt.Errorf("failed to add target: %v", err)
 	}
 
 	// Log progress
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"step":      "1. Add target",
 		"target_id": targetID,
 		"status":    "completed",
@@ -848,25 +843,25 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 	targetExists, err := checkTargetExists(targetID)
 	if err != nil || !targetExists {
 		removeTarget(targetID)
-		return fmt.Errorf("failed to verify target: %v", err)
+		return result, fmt.Errorf("failed to verify target: %v", err)
 	}
 
 	// Log progress
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"step":      "2. Check target exists",
 		"target_id": targetID,
 		"status":    "completed",
 	})
 
 	// Step 3: Add scan with scan profile ID
-	scanID, err := startScan(targetID, scanProfileID)
+	scanID, err := startScan(targetID, profileID)
 	if err != nil {
 		removeTarget(targetID)
-		return fmt.Errorf("failed to start scan: %v", err)
+		return result, fmt.Errorf("failed to start scan: %v", err)
 	}
 
 	// Log progress
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"step":    "3. Start scan",
 		"scan_id": scanID,
 		"status":  "completed",
@@ -877,11 +872,11 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 	if err != nil || !scanExists {
 		removeScan(scanID)
 		removeTarget(targetID)
-		return fmt.Errorf("failed to verify scan: %v", err)
+		return result, fmt.Errorf("failed to verify scan: %v", err)
 	}
 
 	// Log progress
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"step":    "4. Check scan exists",
 		"scan_id": scanID,
 		"status":  "completed",
@@ -890,91 +885,144 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 	// Step 5: Wait for scan status to be completed
 	scanCompleted, err := waitForScanCompletion(scanID, waitTimeout)
 	if err != nil || !scanCompleted {
+		message := fmt.Sprintf("Scan timed out after %d seconds", waitTimeout)
+		if err != nil {
+			message = fmt.Sprintf("Error waiting for scan: %v", err)
+		}
+		progress(map[string]interface{}{
+			"step":          "Notifications",
+			"scan_id":       scanID,
+			"notifications": notify.Dispatch([]notify.Event{{Type: notify.EventScanFailed, TargetID: targetID, Address: opts.TargetURL, ScanID: scanID, Message: message}}),
+		})
+
 		removeScan(scanID)
 		removeTarget(targetID)
 		if err != nil {
-			return fmt.Errorf("error waiting for scan: %v", err)
+			return result, fmt.Errorf("error waiting for scan: %v", err)
 		}
-		return fmt.Errorf("scan timed out after %d seconds", waitTimeout)
+		return result, fmt.Errorf("scan timed out after %d seconds", waitTimeout)
 	}
 
 	// Log progress
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"step":    "5. Wait for scan completion",
 		"scan_id": scanID,
 		"status":  "completed",
 	})
 
+	// The vulnerabilities are checked before the scan is removed
+	result.TargetID = targetID
+	result.ScanID = scanID
+	outcome, err := notify.FinishedScanEvents(scanID, gate)
+	if err != nil {
+		progress(map[string]interface{}{"error": fmt.Sprintf("Error checking scan vulnerabilities: %v", err)})
+	} else {
+		result.Counts = outcome.Counts
+		result.GateFailed = outcome.GateFailed
+		step := map[string]interface{}{
+			"step":          "Notifications",
+			"scan_id":       scanID,
+			"counts":        outcome.Counts,
+			"gate_failed":   outcome.GateFailed,
+			"notifications": notify.Dispatch(outcome.Events),
+		}
+		if len(outcome.Warnings) > 0 {
+			step["warnings"] = outcome.Warnings
+		}
+		progress(step)
+	}
+
+	// Vulnerability management formats are written from the scan data, no report is generated
+	if format == vulnformat.FormatDefectDojo || format == vulnformat.FormatGenericFindings {
+		file, err := writeFindings(scanID, format, outputPath)
+		removeScan(scanID)
+		removeTarget(targetID)
+		if err != nil {
+			return result, fmt.Errorf("failed to write findings: %v", err)
+		}
+
+		progress(map[string]interface{}{
+			"status":    "success",
+			"message":   "Auto process completed successfully",
+			"target_id": targetID,
+			"scan_id":   scanID,
+			"files":     []string{file},
+		})
+
+		result.Files = []string{file}
+		return result, nil
+	}
+
 	// Step 6: Generate report or create export based on format
 	var reportID string
 	var downloadLinks []string
 
-	if strings.ToLower(outputFormat) == "csv" {
+	if format == "csv" {
 		// Create export for CSV format
 		reportID, err = createExport("21111111-1111-1111-1111-111111111141", []string{scanID})
 		if err != nil {
 			removeScan(scanID)
 			removeTarget(targetID)
-			return fmt.Errorf("failed to create export: %v", err)
+			return result, fmt.Errorf("failed to create export: %v", err)
 		}
 
 		// Log progress
-		jsonoutput.OutputJSON(map[string]interface{}{
+		progress(map[string]interface{}{
 			"step":      "6. Create export",
 			"report_id": reportID,
 			"status":    "completed",
 		})
 
 		// Wait for export completion
-		downloadLinks, err = waitForExportCompletion(reportID, waitTimeout)
+		downloadLinks, err = export.WaitForExportCompletion(reportID, waitTimeout)
 		if err != nil {
-			removeExport(reportID)
+			export.RemoveExport(reportID)
 			removeScan(scanID)
 			removeTarget(targetID)
-			return fmt.Errorf("error waiting for export: %v", err)
+			return result, fmt.Errorf("error waiting for export: %v", err)
 		}
 	} else {
 		// Generate HTML report
-		reportID, err = generateReport(reportTemplateID, "Auto-generated report", "scan_result", []string{scanID})
+		reportID, err = generateReport(templateID, "Auto-generated report", "scan_result", []string{scanID})
 		if err != nil {
 			removeScan(scanID)
 			removeTarget(targetID)
-			return fmt.Errorf("failed to generate report: %v", err)
+			return result, fmt.Errorf("failed to generate report: %v", err)
 		}
 
 		// Log progress
-		jsonoutput.OutputJSON(map[string]interface{}{
+		progress(map[string]interface{}{
 			"step":      "6. Generate report",
 			"report_id": reportID,
 			"status":    "completed",
 		})
 
 		// Wait for report completion
-		downloadLinks, err = waitForReportCompletion(reportID, waitTimeout)
+		downloadLinks, err = waitForReportCompletion(reportID, waitTimeout, format)
 		if err != nil {
 			removeReport(reportID)
 			removeScan(scanID)
 			removeTarget(targetID)
-			return fmt.Errorf("error waiting for report: %v", err)
+			return result, fmt.Errorf("error waiting for report: %v", err)
 		}
 	}
 
 	// Step 7: Download report/export files
 	downloadedFiles, err := downloadReportFiles(downloadLinks, outputPath)
 	if err != nil {
-		if strings.ToLower(outputFormat) == "csv" {
-			removeExport(reportID)
+		if format == "csv" {
+			export.RemoveExport(reportID)
 		} else {
 			removeReport(reportID)
 		}
 		removeScan(scanID)
 		removeTarget(targetID)
-		return fmt.Errorf("failed to download files: %v", err)
+		return result, fmt.Errorf("failed to download files: %v", err)
 	}
 
 	// Clean up resources
-	if strings.ToLower(outputFormat) == "csv" {
-		removeExport(reportID)
+	if format == "csv" {
+		export.RemoveExport(reportID)
 	} else {
 		removeReport(reportID)
 	}
@@ -982,7 +1030,7 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 	removeTarget(targetID)
 
 	// Final success output
-	jsonoutput.OutputJSON(map[string]interface{}{
+	progress(map[string]interface{}{
 		"status":    "success",
 		"message":   "Auto process completed successfully",
 		"target_id": targetID,
@@ -991,11 +1039,15 @@ func RunAutoCommand(targetURL string, waitTimeout int, outputPath string, output
 		"files":     downloadedFiles,
 	})
 
-	return nil
+	result.ReportID = reportID
+	result.Files = downloadedFiles
+	return result, nil
 }
 
 func init() {
 	// Remove existing flag definitions since they're now global
-	AutoCmd.Flags().StringVarP(&scanProfileID, "scanProfileID", "s", "", "Scan profile ID to use")
-	AutoCmd.Flags().StringVarP(&reportTemplateID, "reportTemplateID", "r", "", "Report template ID to use")
+	AutoCmd.Flags().StringVarP(&scanProfileID, "scanProfileID", "s", "", "Scan profile ID or name to use")
+	AutoCmd.Flags().StringVarP(&reportTemplateID, "reportTemplateID", "r", "", "Report template ID or name to use")
+	completion.Register(AutoCmd, resolver.KindScanProfile, "scanProfileID")
+	completion.Register(AutoCmd, resolver.KindReportTemplate, "reportTemplateID")
 }
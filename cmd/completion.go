@@ -0,0 +1,47 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the shell completion script",
+	Long: `Generates the completion script for the given shell. Besides commands and flags, IDs given with --id, --gid,
+--scanProfileID, --template and --export-id are completed from the server in the config, described by the
+target address or name. Lookups are cached for a few minutes. Example:
+
+Bash:
+  source <(acucli completion bash)
+  acucli completion bash > /etc/bash_completion.d/acucli : Loads it for every session
+
+Zsh:
+  acucli completion zsh > "${fpath[1]}/_acucli"
+
+Fish:
+  acucli completion fish > ~/.config/fish/completions/acucli.fish
+
+PowerShell:
+  acucli completion powershell | Out-String | Invoke-Expression`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(os.Stdout)
+		case "fish":
+			return cmd.Root().GenFishCompletion(os.Stdout, true)
+		default:
+			return cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package excludedHours
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// addCmd represents the add command
+var AddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an excluded hours profile",
+	Long: `Creates an excluded hours profile from weekly blocks. --block can be repeated. Example:
+
+acucli excludedHours add --name "Office hours" --block "Mon-Fri 08:00-20:00"
+acucli excludedHours add --name "Weekend and nights" --block "Sat,Sun 00:00-24:00" --block "Mon-Fri 20:00-06:00"
+acucli excludedHours add --name "Office hours CET" --block "Mon-Fri 08:00-20:00" --time-offset 60`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		blocks, _ := cmd.Flags().GetStringArray("block")
+		timeOffset, _ := cmd.Flags().GetInt("time-offset")
+
+		if name == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--name is required"), "Error")
+			return
+		}
+		if len(blocks) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("at least one --block is required"), "Error")
+			return
+		}
+
+		matrix, err := parseBlocks(blocks)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+
+		profile := ExcludedHoursProfile{Name: name, TimeOffset: timeOffset, ExclusionMatrix: matrix}
+		requestJson, err := json.Marshal(profile)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
+			return
+		}
+
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/excluded_hours_profiles", viper.GetString("URL")), bytes.NewBuffer(requestJson))
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating request")
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpclient.MyHTTPClient.Do(req)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error making request")
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
+			return
+		}
+
+		if resp.StatusCode >= 300 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("status code: %d, response: %s", resp.StatusCode, string(body)), "Error adding excluded hours profile")
+			return
+		}
+		resolver.Invalidate(resolver.KindExcludedHours)
+
+		// Output only the JSON response
+		jsonoutput.OutputRawJSON(body)
+	},
+}
+
+func init() {
+	AddCmd.Flags().String("name", "", "Name of the profile")
+	AddCmd.Flags().StringArray("block", []string{}, "Excluded block as \"<days> <HH:MM>-<HH:MM>\", can be repeated (e.g. \"Mon-Fri 08:00-20:00\")")
+	AddCmd.Flags().Int("time-offset", 0, "Time zone offset of the blocks from UTC in minutes")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// addCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// addCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
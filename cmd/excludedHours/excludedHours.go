@@ -0,0 +1,92 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package excludedHours
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+type ExcludedHoursProfile struct {
+	ExcludedHoursID string `json:"excluded_hours_id,omitempty"`
+	Name            string `json:"name"`
+	TimeOffset      int    `json:"time_offset"`
+	ExclusionMatrix []bool `json:"exclusion_matrix"`
+}
+
+type ExcludedHoursProfiles struct {
+	Values []ExcludedHoursProfile `json:"values"`
+}
+
+// ExcludedHoursCmd represents the excludedHours command
+var ExcludedHoursCmd = &cobra.Command{
+	Use:     "excludedHours",
+	Aliases: []string{"excluded-hours"},
+	Short:   "Manage excluded hours profiles",
+	Long: `Excluded hours profiles define the weekly hours in which scans are paused. Blocks are written as
+"<days> <HH:MM>-<HH:MM>", for example "Mon-Fri 08:00-20:00" or "daily 22:00-06:00". Example:
+
+acucli excludedHours list
+acucli excludedHours add --name "Office hours" --block "Mon-Fri 08:00-20:00"
+echo "<EXCLUDED-HOURS-ID>" | acucli excludedHours remove`,
+}
+
+// Fetch the excluded hours profiles
+func fetchExcludedHoursProfiles() ([]ExcludedHoursProfile, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/excluded_hours_profiles", viper.GetString("URL")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error listing excluded hours profiles, status code: %d", resp.StatusCode)
+	}
+
+	var profiles ExcludedHoursProfiles
+	err = json.Unmarshal(body, &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return profiles.Values, nil
+}
+
+// ResolveExcludedHoursID returns the ID of an excluded hours profile given by ID or name
+func ResolveExcludedHoursID(profile string) (string, error) {
+	return resolver.Resolve(resolver.KindExcludedHours, profile)
+}
+
+func init() {
+	ExcludedHoursCmd.AddCommand(ListCmd)
+	ExcludedHoursCmd.AddCommand(AddCmd)
+	ExcludedHoursCmd.AddCommand(RemoveCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// excludedHoursCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// excludedHoursCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
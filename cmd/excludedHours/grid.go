@@ -0,0 +1,164 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package excludedHours
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The exclusion matrix has one entry per hour of the week, starting on Sunday 00:00
+const hoursPerWeek = 7 * 24
+
+var dayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// Parse weekly blocks such as "Mon-Fri 08:00-20:00", "Sat,Sun 00:00-24:00" or "daily 22:00-06:00"
+// into an exclusion matrix. A block ending before it starts runs into the next day.
+func parseBlocks(blocks []string) ([]bool, error) {
+	matrix := make([]bool, hoursPerWeek)
+
+	for _, block := range blocks {
+		fields := strings.Fields(block)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid block %q, expected \"<days> <HH:MM>-<HH:MM>\"", block)
+		}
+
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %q: %v", block, err)
+		}
+
+		startText, endText, found := strings.Cut(fields[1], "-")
+		if !found {
+			return nil, fmt.Errorf("invalid block %q, expected a time range like 08:00-20:00", block)
+		}
+		start, err := parseHour(startText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %q: %v", block, err)
+		}
+		end, err := parseHour(endText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %q: %v", block, err)
+		}
+		if start == 24 || start == end {
+			return nil, fmt.Errorf("invalid block %q, empty time range", block)
+		}
+		if end < start {
+			end += 24
+		}
+
+		for _, day := range days {
+			for hour := start; hour < end; hour++ {
+				matrix[(day*24+hour)%hoursPerWeek] = true
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// Parse "Mon-Fri", "Sat,Sun", "Wed" or "daily" into day indexes
+func parseDays(text string) ([]int, error) {
+	if strings.EqualFold(text, "daily") || text == "*" {
+		return []int{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+
+	var days []int
+	for _, part := range strings.Split(text, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := dayIndex(from)
+		if err != nil {
+			return nil, err
+		}
+		if !isRange {
+			days = append(days, first)
+			continue
+		}
+		last, err := dayIndex(to)
+		if err != nil {
+			return nil, err
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days = append(days, day)
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// Days are given by their three-letter abbreviation or their full name, e.g. Mon or Monday
+func dayIndex(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	for i, day := range dayNames {
+		if strings.EqualFold(name, day) || strings.EqualFold(name, time.Weekday(i).String()) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", name)
+}
+
+// Parse HH:MM into an hour, the matrix has a granularity of one hour
+func parseHour(text string) (int, error) {
+	hourText, minuteText, found := strings.Cut(strings.TrimSpace(text), ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("invalid time %q", text)
+	}
+	if found && minuteText != "00" {
+		return 0, fmt.Errorf("invalid time %q, only full hours are supported", text)
+	}
+	return hour, nil
+}
+
+// Describe an exclusion matrix as blocks, days with the same hours are grouped together
+func describeMatrix(matrix []bool) []string {
+	if len(matrix) != hoursPerWeek {
+		return nil
+	}
+
+	// Ranges of excluded hours for each day, Monday first
+	order := []int{1, 2, 3, 4, 5, 6, 0}
+	ranges := make([]string, 7)
+	for _, day := range order {
+		var parts []string
+		for hour := 0; hour < 24; {
+			if !matrix[day*24+hour] {
+				hour++
+				continue
+			}
+			start := hour
+			for hour < 24 && matrix[day*24+hour] {
+				hour++
+			}
+			parts = append(parts, fmt.Sprintf("%02d:00-%02d:00", start, hour))
+		}
+		ranges[day] = strings.Join(parts, ",")
+	}
+
+	var blocks []string
+	for i := 0; i < len(order); {
+		day := order[i]
+		j := i
+		for j+1 < len(order) && ranges[order[j+1]] == ranges[day] {
+			j++
+		}
+		if ranges[day] != "" {
+			days := dayNames[day]
+			if j > i {
+				days = fmt.Sprintf("%s-%s", dayNames[day], dayNames[order[j]])
+			}
+			for _, r := range strings.Split(ranges[day], ",") {
+				blocks = append(blocks, fmt.Sprintf("%s %s", days, r))
+			}
+		}
+		i = j + 1
+	}
+
+	return blocks
+}
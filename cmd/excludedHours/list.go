@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package excludedHours
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// listCmd represents the list command
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the excluded hours profiles",
+	Long:  `Lists the excluded hours profiles with their IDs and their excluded hours as weekly blocks`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := fetchExcludedHoursProfiles()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing excluded hours profiles")
+			return
+		}
+
+		result := []map[string]interface{}{}
+		for _, p := range profiles {
+			blocks := describeMatrix(p.ExclusionMatrix)
+			if blocks == nil {
+				blocks = []string{}
+			}
+			result = append(result, map[string]interface{}{
+				"excluded_hours_id": p.ExcludedHoursID,
+				"name":              p.Name,
+				"time_offset":       p.TimeOffset,
+				"blocks":            blocks,
+			})
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(map[string]interface{}{"values": result})
+	},
+}
+
+func init() {
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// listCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// downloadExportCmd represents the download command
+var downloadExportCmd = &cobra.Command{
+	Use:   "download [export_id...]",
+	Short: "Download export files",
+	Long: `Waits for the given exports to complete and downloads their files.
+Takes export IDs as arguments or from stdin. Example:
+
+acucli export download 5fac63fd-088c-4445-a2bf-a9f03f014832 --output=./exports
+echo "scan_id_here" | acucli export create --export-id=JSON | jq -r .report_id | acucli export download`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exportIDs := exportIDsFromInput(args)
+		if len(exportIDs) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no export IDs provided"), "Error")
+			return
+		}
+
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		outputDir, _ := cmd.Flags().GetString("output")
+
+		if outputDir != "" {
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error creating output directory")
+				return
+			}
+		}
+
+		results := make(map[string]interface{})
+		for _, exportID := range exportIDs {
+			links, err := WaitForExportCompletion(exportID, timeout)
+			if err != nil {
+				results[exportID] = map[string]interface{}{
+					"status": "error",
+					"error":  err.Error(),
+				}
+				continue
+			}
+
+			files, err := DownloadExportFiles(links, outputDir)
+			if err != nil {
+				results[exportID] = map[string]interface{}{
+					"status": "error",
+					"error":  err.Error(),
+					"files":  files,
+				}
+				continue
+			}
+
+			results[exportID] = map[string]interface{}{
+				"status": "success",
+				"files":  files,
+			}
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+// DownloadExportFiles downloads the given export links into the output directory
+func DownloadExportFiles(downloadLinks []string, outputDir string) ([]string, error) {
+	var downloadedFiles []string
+
+	for _, link := range downloadLinks {
+		// Avoid duplicate path segments when the link already contains the API prefix
+		baseURL := viper.GetString("URL")
+		if filepath.Base(baseURL) == "v1" && strings.HasPrefix(link, "/api/v1") {
+			baseURL = strings.TrimSuffix(baseURL, "/api/v1")
+		}
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", baseURL, link), nil)
+		if err != nil {
+			return downloadedFiles, fmt.Errorf("error creating request: %v", err)
+		}
+
+		resp, err := httpclient.MyHTTPClient.Do(req)
+		if err != nil {
+			return downloadedFiles, fmt.Errorf("error making request: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return downloadedFiles, fmt.Errorf("error downloading %s, status code: %d", link, resp.StatusCode)
+		}
+
+		filePath := filepath.Join(outputDir, filepath.Base(link))
+		out, err := os.Create(filePath)
+		if err != nil {
+			resp.Body.Close()
+			return downloadedFiles, fmt.Errorf("error creating file: %v", err)
+		}
+
+		_, err = io.Copy(out, resp.Body)
+		resp.Body.Close()
+		out.Close()
+		if err != nil {
+			return downloadedFiles, fmt.Errorf("error writing to file: %v", err)
+		}
+
+		downloadedFiles = append(downloadedFiles, filePath)
+	}
+
+	return downloadedFiles, nil
+}
+
+func init() {
+	downloadExportCmd.Flags().IntP("timeout", "i", 800, "Timeout in seconds for each export")
+	downloadExportCmd.Flags().StringP("output", "o", "", "Output directory for downloaded files")
+}
@@ -12,9 +12,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 // ExportCmd represents the export command
@@ -36,6 +38,19 @@ type ExportRequest struct {
 	Source   ExportSource `json:"source"`
 }
 
+// ExportType represents an export type returned by /export_types
+type ExportType struct {
+	ExportID        string   `json:"export_id"`
+	Name            string   `json:"name"`
+	ContentType     string   `json:"content_type"`
+	AcceptedSources []string `json:"accepted_sources"`
+}
+
+// ExportTypes represents the response of /export_types
+type ExportTypes struct {
+	Templates []ExportType `json:"templates"`
+}
+
 // getExportTypesCmd represents the get_export_types command
 var getExportTypesCmd = &cobra.Command{
 	Use:   "get_export_types",
@@ -133,7 +148,8 @@ echo "scan_id_here" | acucli export create
 cat scan_ids.txt | acucli export create
 
 By default, the command uses "scans" as the list type and a predefined export ID.
-You can override these defaults with the --list-type and --export-id flags.`,
+You can override these defaults with the --list-type and --export-id flags.
+The --export-id flag accepts an export type ID or name, e.g. --export-id="JSON".`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get the list type from flags, default to "scans"
 		listType, _ := cmd.Flags().GetString("list-type")
@@ -147,6 +163,12 @@ You can override these defaults with the --list-type and --export-id flags.`,
 			exportID = "21111111-1111-1111-1111-111111111141"
 		}
 
+		exportID, err := ResolveExportTypeID(exportID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving export type")
+			return
+		}
+
 		// Read IDs from stdin
 		idList := filehelper.ReadStdin()
 		if idList == nil || len(idList) == 0 {
@@ -206,13 +228,32 @@ You can override these defaults with the --list-type and --export-id flags.`,
 	},
 }
 
+// ResolveExportTypeID returns the export type ID for the given UUID or export type name.
+// Names are matched case-insensitively against the server's export types.
+func ResolveExportTypeID(exportType string) (string, error) {
+	return resolver.Resolve(resolver.KindExportType, exportType)
+}
+
+// Read export IDs from the arguments, falling back to stdin
+func exportIDsFromInput(args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+	return filehelper.ReadStdin()
+}
+
 func init() {
 	// Add subcommands to the export command
 	ExportCmd.AddCommand(getExportTypesCmd)
 	ExportCmd.AddCommand(getExportCmd)
 	ExportCmd.AddCommand(createExportCmd)
+	ExportCmd.AddCommand(listExportsCmd)
+	ExportCmd.AddCommand(waitExportCmd)
+	ExportCmd.AddCommand(downloadExportCmd)
+	ExportCmd.AddCommand(removeExportCmd)
 
 	// Add flags to the create command
 	createExportCmd.Flags().String("list-type", "", "Type of list (e.g., 'scans', defaults to 'scans')")
-	createExportCmd.Flags().String("export-id", "", "Optional export ID (defaults to '21111111-1111-1111-1111-111111111141')")
+	createExportCmd.Flags().String("export-id", "", "Optional export type ID or name (defaults to '21111111-1111-1111-1111-111111111141')")
+	completion.Register(createExportCmd, resolver.KindExportType, "export-id")
 }
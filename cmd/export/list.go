@@ -0,0 +1,57 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// listExportsCmd represents the list command
+var listExportsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all exports",
+	Long:  `Lists all exports with their status and download links.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Create the request
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/exports", viper.GetString("URL")), nil)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating request")
+			return
+		}
+
+		// Perform the request
+		resp, err := httpclient.MyHTTPClient.Do(req)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error making request")
+			return
+		}
+		defer resp.Body.Close()
+
+		// Read the response body
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
+			return
+		}
+
+		// Check if the response is valid JSON
+		var responseBody interface{}
+		err = json.Unmarshal(body, &responseBody)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON response")
+			return
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputRawJSON(body)
+	},
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// RemoveExportRequest represents the request body for removing exports
+type RemoveExportRequest struct {
+	ExportIDList []string `json:"export_id_list"`
+}
+
+// removeExportCmd represents the remove command
+var removeExportCmd = &cobra.Command{
+	Use:   "remove [export_id...]",
+	Short: "Remove exports",
+	Long: `Remove exports by ID. Takes export IDs as arguments or from stdin. Example:
+
+acucli export remove 5fac63fd-088c-4445-a2bf-a9f03f014832
+cat export_ids.txt | acucli export remove : Removes multiple exports`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exportIDs := exportIDsFromInput(args)
+		if len(exportIDs) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no export IDs provided"), "Error")
+			return
+		}
+
+		if err := RemoveExports(exportIDs); err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error removing exports")
+			return
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"status":      "success",
+			"removed_ids": exportIDs,
+		})
+	},
+}
+
+// RemoveExport removes a single export
+func RemoveExport(exportID string) error {
+	return RemoveExports([]string{exportID})
+}
+
+// RemoveExports removes the given exports
+func RemoveExports(exportIDs []string) error {
+	request := RemoveExportRequest{
+		ExportIDList: exportIDs,
+	}
+
+	requestJson, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/exports/delete"), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("error removing export, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// waitExportCmd represents the wait command
+var waitExportCmd = &cobra.Command{
+	Use:   "wait [export_id...]",
+	Short: "Wait for exports to complete",
+	Long: `Waits until the given exports are completed and prints their download links.
+Takes export IDs as arguments or from stdin. Example:
+
+acucli export wait 5fac63fd-088c-4445-a2bf-a9f03f014832
+echo "export_id_here" | acucli export wait --timeout=300`,
+	Run: func(cmd *cobra.Command, args []string) {
+		exportIDs := exportIDsFromInput(args)
+		if len(exportIDs) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no export IDs provided"), "Error")
+			return
+		}
+
+		timeout, _ := cmd.Flags().GetInt("timeout")
+
+		results := make(map[string]interface{})
+		for _, exportID := range exportIDs {
+			links, err := WaitForExportCompletion(exportID, timeout)
+			if err != nil {
+				results[exportID] = map[string]interface{}{
+					"status": "error",
+					"error":  err.Error(),
+				}
+				continue
+			}
+			results[exportID] = map[string]interface{}{
+				"status":   "completed",
+				"download": links,
+			}
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+// WaitForExportCompletion waits for an export to complete and returns its download links
+func WaitForExportCompletion(exportID string, timeoutSeconds int) ([]string, error) {
+	startTime := time.Now()
+	timeout := time.Duration(timeoutSeconds) * time.Second
+
+	for {
+		// Check if timeout has been reached
+		if time.Since(startTime) > timeout {
+			return nil, fmt.Errorf("timeout waiting for export completion")
+		}
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/exports/%s", viper.GetString("URL"), exportID), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %v", err)
+		}
+
+		resp, err := httpclient.MyHTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error making request: %v", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("export does not exist, status code: %d", resp.StatusCode)
+		}
+
+		var exportResponse map[string]interface{}
+		err = json.Unmarshal(body, &exportResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing response: %v", err)
+		}
+
+		// Check if export is completed
+		status, _ := exportResponse["status"].(string)
+		if status == "completed" {
+			// Get download links
+			if download, ok := exportResponse["download"].([]interface{}); ok {
+				var links []string
+				for _, link := range download {
+					if linkStr, ok := link.(string); ok {
+						links = append(links, linkStr)
+					}
+				}
+				return links, nil
+			}
+			return nil, fmt.Errorf("no download links found in response")
+		} else if status == "failed" {
+			return nil, fmt.Errorf("export failed")
+		}
+
+		// Wait for 5 seconds before checking again
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func init() {
+	waitExportCmd.Flags().IntP("timeout", "i", 800, "Timeout in seconds for each export")
+}
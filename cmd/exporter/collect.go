@@ -0,0 +1,125 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package exporter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Scan statuses counted as running
+var runningStatuses = map[string]bool{
+	"queued":     true,
+	"starting":   true,
+	"processing": true,
+	"aborting":   true,
+	"pausing":    true,
+}
+
+// Scan statuses reported even when no scan has them, so the series do not disappear
+var scanStatuses = []string{"scheduled", "queued", "starting", "processing", "aborting", "aborted", "pausing", "paused", "completed", "failed"}
+
+// Poll the server and build the scanner metrics. The vulnerabilities of a target are the
+// severity counts of its latest completed scan, those of a group are summed over its targets.
+func collect() ([]metric, error) {
+	targets, err := scandata.FetchTargets()
+	if err != nil {
+		return nil, err
+	}
+	scans, err := scandata.FetchScans()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := scandata.FetchTargetGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	statusCounts := make(map[string]int)
+	for _, status := range scanStatuses {
+		statusCounts[status] = 0
+	}
+	running := 0
+	lastStart := make(map[string]time.Time)
+	lastCompleted := make(map[string]scandata.Scan)
+	for _, scan := range scans {
+		status := scan.CurrentSession.Status
+		if status == "" {
+			status = "scheduled"
+		}
+		statusCounts[status]++
+		if runningStatuses[status] {
+			running++
+		}
+
+		start, err := time.Parse(time.RFC3339, scan.CurrentSession.StartDate)
+		if err != nil {
+			continue
+		}
+		if start.After(lastStart[scan.TargetID]) {
+			lastStart[scan.TargetID] = start
+		}
+		if status == "completed" {
+			previous, ok := lastCompleted[scan.TargetID]
+			if !ok || scan.CurrentSession.StartDate > previous.CurrentSession.StartDate {
+				lastCompleted[scan.TargetID] = scan
+			}
+		}
+	}
+
+	scansByStatus := metric{name: "acunetix_scans", help: "Scans by the status of their current session.", kind: "gauge"}
+	statuses := make([]string, 0, len(statusCounts))
+	for status := range statusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		scansByStatus.add(float64(statusCounts[status]), "status", status)
+	}
+
+	runningScans := metric{name: "acunetix_scans_running", help: "Scans which are queued, starting, processing, aborting or pausing.", kind: "gauge"}
+	runningScans.add(float64(running))
+
+	targetCount := metric{name: "acunetix_targets", help: "Number of targets.", kind: "gauge"}
+	targetCount.add(float64(len(targets)))
+
+	targetVulnerabilities := metric{name: "acunetix_target_vulnerabilities", help: "Vulnerabilities of the latest completed scan of a target by severity.", kind: "gauge"}
+	lastScanAge := metric{name: "acunetix_target_last_scan_age_seconds", help: "Seconds since the latest scan of a target started, targets never scanned are left out.", kind: "gauge"}
+	addresses := make(map[string]string)
+	counts := make(map[string]map[string]int)
+	now := time.Now()
+	for _, target := range targets {
+		addresses[target.TargetID] = target.Address
+		if scan, ok := lastCompleted[target.TargetID]; ok {
+			counts[target.TargetID] = scan.CurrentSession.SeverityCounts
+		}
+		for _, severity := range scandata.SeverityNames {
+			targetVulnerabilities.add(float64(counts[target.TargetID][severity]), "target_id", target.TargetID, "address", target.Address, "severity", severity)
+		}
+		if start, ok := lastStart[target.TargetID]; ok {
+			lastScanAge.add(now.Sub(start).Seconds(), "target_id", target.TargetID, "address", target.Address)
+		}
+	}
+
+	groupVulnerabilities := metric{name: "acunetix_group_vulnerabilities", help: "Vulnerabilities of the latest completed scans of the targets of a group by severity.", kind: "gauge"}
+	for _, group := range groups {
+		targetIDs, err := scandata.FetchGroupTargets(group.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		sums := make(map[string]int)
+		for _, targetID := range targetIDs {
+			for severity, count := range counts[targetID] {
+				sums[severity] += count
+			}
+		}
+		for _, severity := range scandata.SeverityNames {
+			groupVulnerabilities.add(float64(sums[severity]), "group_id", group.GroupID, "group", group.Name, "severity", severity)
+		}
+	}
+
+	return []metric{scansByStatus, runningScans, targetCount, targetVulnerabilities, lastScanAge, groupVulnerabilities}, nil
+}
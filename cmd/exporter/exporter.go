@@ -0,0 +1,150 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// exporterState holds the metrics of the latest poll
+type exporterState struct {
+	mu           sync.Mutex
+	scanner      []metric
+	up           bool
+	lastPoll     time.Time
+	pollDuration time.Duration
+	pollErrors   float64
+}
+
+// Poll the server and keep the metrics. The metrics of the previous poll are kept when a poll fails.
+func (s *exporterState) poll() {
+	start := time.Now()
+	metrics, err := collect()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPoll = start
+	s.pollDuration = time.Since(start)
+	s.up = err == nil
+	if err != nil {
+		s.pollErrors++
+		fmt.Fprintf(os.Stderr, "error polling the scanner: %v\n", err)
+		return
+	}
+	s.scanner = metrics
+}
+
+func (s *exporterState) metrics() []metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	up := metric{name: "acunetix_up", help: "Whether the latest poll of the Acunetix API succeeded.", kind: "gauge"}
+	if s.up {
+		up.add(1)
+	} else {
+		up.add(0)
+	}
+	lastPoll := metric{name: "acunetix_last_poll_timestamp_seconds", help: "Unix time of the latest poll.", kind: "gauge"}
+	lastPoll.add(float64(s.lastPoll.Unix()))
+	pollDuration := metric{name: "acunetix_poll_duration_seconds", help: "Duration of the latest poll.", kind: "gauge"}
+	pollDuration.add(s.pollDuration.Seconds())
+	pollErrors := metric{name: "acunetix_poll_errors_total", help: "Polls which failed.", kind: "counter"}
+	pollErrors.add(s.pollErrors)
+
+	return append([]metric{up, lastPoll, pollDuration, pollErrors}, s.scanner...)
+}
+
+// exporterCmd represents the exporter command
+var ExporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Expose scanner metrics for Prometheus",
+	Long: `Polls the targets, target groups and scans every --interval seconds and serves Prometheus metrics on /metrics:
+scans by status, running scans, vulnerabilities by severity per target and group (of the latest completed scans),
+seconds since the latest scan per target, and the request count, errors and latency of the Acunetix API. Example:
+
+acucli exporter --listen :9750 --interval 60`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		interval, _ := cmd.Flags().GetInt("interval")
+		if interval < 1 {
+			interval = 1
+		}
+
+		api := newAPIStats()
+		httpclient.RequestObserver = api.observe
+
+		state := &exporterState{}
+		state.poll()
+		go func() {
+			ticker := time.NewTicker(time.Duration(interval) * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				state.poll()
+			}
+		}()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+			var buf bytes.Buffer
+			writeMetrics(&buf, append(state.metrics(), api.metrics()...))
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			w.Write(buf.Bytes())
+		})
+		mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "acucli exporter, metrics are served on /metrics")
+		})
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			server.Shutdown(shutdown)
+		}()
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"status":   "listening",
+			"listen":   listen,
+			"interval": interval,
+		})
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("error serving: %v", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	ExporterCmd.Flags().String("listen", ":9750", "Address to serve the metrics on")
+	ExporterCmd.Flags().Int("interval", 60, "Seconds between polls of the scanner")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// exporterCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// exporterCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Buckets of the API request duration histogram in seconds
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// sample is one value of a metric, histograms use the suffix for their _bucket, _sum and _count series
+type sample struct {
+	suffix string
+	labels [][2]string
+	value  float64
+}
+
+// metric is a metric family written in the Prometheus text format
+type metric struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+func (m *metric) add(value float64, labels ...string) {
+	s := sample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels = append(s.labels, [2]string{labels[i], labels[i+1]})
+	}
+	m.samples = append(m.samples, s)
+}
+
+// Write metric families in the Prometheus text exposition format
+func writeMetrics(w io.Writer, metrics []metric) {
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+		for _, s := range m.samples {
+			fmt.Fprintf(w, "%s%s%s %s\n", m.name, s.suffix, formatLabels(s.labels), formatValue(s.value))
+		}
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf(`%s="%s"`, label[0], labelEscaper.Replace(label[1]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// histogram counts observations per bucket, the counts are made cumulative when written
+type histogram struct {
+	counts []float64
+	sum    float64
+	count  float64
+}
+
+// apiStats counts the requests made to the Acunetix API
+type apiStats struct {
+	mu        sync.Mutex
+	requests  map[[2]string]float64
+	errors    map[string]float64
+	durations map[string]*histogram
+}
+
+func newAPIStats() *apiStats {
+	return &apiStats{
+		requests:  make(map[[2]string]float64),
+		errors:    make(map[string]float64),
+		durations: make(map[string]*histogram),
+	}
+}
+
+// Record a request, used as httpclient.RequestObserver
+func (s *apiStats) observe(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	name := endpoint(req.URL.Path)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[[2]string{name, code}]++
+	if err != nil || resp.StatusCode >= 400 {
+		s.errors[name]++
+	}
+
+	h, ok := s.durations[name]
+	if !ok {
+		h = &histogram{counts: make([]float64, len(latencyBuckets))}
+		s.durations[name] = h
+	}
+	seconds := duration.Seconds()
+	for i, bucket := range latencyBuckets {
+		if seconds <= bucket {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// The first path segment after the API base path, e.g. "scans" for /api/v1/scans/<id>/results.
+// IDs are left out to keep the number of series small.
+func endpoint(path string) string {
+	if base, err := url.Parse(viper.GetString("URL")); err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func (s *apiStats) metrics() []metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := metric{name: "acunetix_api_requests_total", help: "Requests made to the Acunetix API by endpoint and status code.", kind: "counter"}
+	keys := make([][2]string, 0, len(s.requests))
+	for key := range s.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, k int) bool {
+		if keys[i][0] != keys[k][0] {
+			return keys[i][0] < keys[k][0]
+		}
+		return keys[i][1] < keys[k][1]
+	})
+	for _, key := range keys {
+		requests.add(s.requests[key], "endpoint", key[0], "code", key[1])
+	}
+
+	errors := metric{name: "acunetix_api_request_errors_total", help: "Failed requests and error responses of the Acunetix API by endpoint.", kind: "counter"}
+	for _, name := range sortedKeys(s.errors) {
+		errors.add(s.errors[name], "endpoint", name)
+	}
+
+	durations := metric{name: "acunetix_api_request_duration_seconds", help: "Duration of requests to the Acunetix API by endpoint.", kind: "histogram"}
+	names := make([]string, 0, len(s.durations))
+	for name := range s.durations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := s.durations[name]
+		cumulative := 0.0
+		for i, bucket := range latencyBuckets {
+			cumulative += h.counts[i]
+			durations.samples = append(durations.samples, sample{suffix: "_bucket", labels: [][2]string{{"endpoint", name}, {"le", formatValue(bucket)}}, value: cumulative})
+		}
+		durations.samples = append(durations.samples, sample{suffix: "_bucket", labels: [][2]string{{"endpoint", name}, {"le", "+Inf"}}, value: h.count})
+		durations.samples = append(durations.samples,
+			sample{suffix: "_sum", labels: [][2]string{{"endpoint", name}}, value: h.sum},
+			sample{suffix: "_count", labels: [][2]string{{"endpoint", name}}, value: h.count})
+	}
+
+	return []metric{requests, errors, durations}
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/store"
+)
+
+// SyncCmd represents the sync command
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Store the scan results and vulnerabilities in the local history database",
+	Long: `Snapshots the targets, completed scan results and their vulnerabilities into the local database (store_path in
+the config, by default acucli.db in the user config directory). Results which are already stored are skipped, so
+sync can run regularly, e.g. from cron. The history is used by "acucli trends". Example:
+
+acucli sync
+acucli sync --target https://shop.example.com
+acucli sync --full : Fetches the stored results again`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		full, _ := cmd.Flags().GetBool("full")
+
+		targetID := ""
+		if targetFlag != "" {
+			id, err := resolver.Resolve(resolver.KindTarget, targetFlag)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving target")
+				return
+			}
+			targetID = id
+		}
+
+		scans, err := scandata.FetchScans()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing scans")
+			return
+		}
+
+		db, err := store.Open()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+		defer db.Close()
+
+		newResults := make(map[string]int)
+		errors := make(map[string]string)
+		for _, scan := range scans {
+			if targetID != "" && scan.TargetID != targetID {
+				continue
+			}
+			count, err := syncScan(db, scan, full)
+			if err != nil {
+				errors[scan.ScanID] = err.Error()
+			}
+			newResults[scan.TargetID] += count
+		}
+
+		targets := make(map[string]interface{})
+		total := 0
+		for id, count := range newResults {
+			findings, err := db.RebuildFindings(id)
+			if err != nil {
+				errors[id] = err.Error()
+				continue
+			}
+			targets[id] = map[string]interface{}{"new_results": count, "findings": findings}
+			total += count
+		}
+
+		result := map[string]interface{}{
+			"store":       store.Path(),
+			"scans":       len(scans),
+			"new_results": total,
+			"targets":     targets,
+		}
+		if len(errors) > 0 {
+			result["errors"] = errors
+		}
+		jsonoutput.OutputJSON(result)
+	},
+}
+
+// Store the target of a scan and its completed results which are not stored yet
+func syncScan(db *store.Store, scan scandata.Scan, full bool) (int, error) {
+	err := db.Put(store.BucketTargets, scan.TargetID, store.TargetRecord{
+		TargetID:    scan.TargetID,
+		Address:     scan.Target.Address,
+		Description: scan.Target.Description,
+		Criticality: scan.Target.Criticality,
+		SyncedAt:    time.Now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := scandata.FetchResults(scan.ScanID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+		if !full {
+			stored, err := db.HasResult(scan.TargetID, result.ResultID)
+			if err != nil {
+				return count, err
+			}
+			if stored {
+				continue
+			}
+		}
+
+		vulnerabilities, err := scandata.FetchVulnerabilities(scan.ScanID, result.ResultID)
+		if err != nil {
+			return count, fmt.Errorf("result %s: %v", result.ResultID, err)
+		}
+
+		snapshot := store.ResultSnapshot{
+			ResultID:    result.ResultID,
+			ScanID:      scan.ScanID,
+			TargetID:    scan.TargetID,
+			Address:     scan.Target.Address,
+			ProfileName: scan.ProfileName,
+			Status:      result.Status,
+			StartDate:   parseDate(result.StartDate),
+			EndDate:     parseDate(result.EndDate),
+			SyncedAt:    time.Now(),
+		}
+		for _, v := range vulnerabilities {
+			snapshot.Findings = append(snapshot.Findings, store.FindingRef{
+				Fingerprint:   v.Fingerprint(scan.TargetID),
+				VtID:          v.VtID,
+				Name:          v.Name,
+				Severity:      v.Severity,
+				AffectsURL:    v.AffectsURL,
+				AffectsDetail: v.AffectsDetail,
+			})
+		}
+
+		err = db.Put(store.BucketResults, store.ResultKey(scan.TargetID, snapshot.StartDate, result.ResultID), snapshot)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Parse a server date, unparsable dates are left zero
+func parseDate(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func init() {
+	SyncCmd.Flags().String("target", "", "Only sync the scans of this target (ID or address)")
+	completion.Register(SyncCmd, resolver.KindTarget, "target")
+	SyncCmd.Flags().Bool("full", false, "Fetch the results which are already stored again")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// syncCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// syncCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
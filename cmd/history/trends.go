@@ -0,0 +1,234 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package history
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/store"
+)
+
+// TrendsCmd represents the trends command
+var TrendsCmd = &cobra.Command{
+	Use:   "trends",
+	Short: "Show the vulnerability trends of a target from the local history",
+	Long: `Reads the history stored by "acucli sync" and shows, for the given period, the severity counts of every completed
+scan result, the mean time to fix per severity and the findings which were fixed and found again. Example:
+
+acucli trends --target https://shop.example.com --since 90d
+acucli trends --target <TARGET-ID> --since 2026-01-01`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+
+		if targetFlag == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target is required"), "Error")
+			return
+		}
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+
+		db, err := store.Open()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+		defer db.Close()
+
+		target, err := findTarget(db, targetFlag)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target")
+			return
+		}
+
+		results, err := db.Results(target.TargetID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading history")
+			return
+		}
+		findings, err := db.Findings(target.TargetID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading history")
+			return
+		}
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"target":           map[string]interface{}{"target_id": target.TargetID, "address": target.Address},
+			"since":            since.Format(time.RFC3339),
+			"results":          severitySeries(results, since),
+			"mean_time_to_fix": meanTimeToFix(findings, since),
+			"reopened":         reopenedFindings(findings, since),
+			"open":             openCounts(findings),
+		})
+	},
+}
+
+// Parse a period ago (90d, 12w, 36h) or a date (2026-01-01)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimRight(value, "dw")); err == nil && n >= 0 {
+		switch {
+		case strings.HasSuffix(value, "d"):
+			return now.AddDate(0, 0, -n), nil
+		case strings.HasSuffix(value, "w"):
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q, use e.g. 90d, 12w, 36h or 2026-01-01", value)
+}
+
+// Find a target in the history by ID or address, or else on the server
+func findTarget(db *store.Store, value string) (store.TargetRecord, error) {
+	targets, err := db.Targets()
+	if err != nil {
+		return store.TargetRecord{}, err
+	}
+	want := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "/")
+	for _, target := range targets {
+		if target.TargetID == value || strings.TrimSuffix(strings.ToLower(target.Address), "/") == want {
+			return target, nil
+		}
+	}
+	if !strings.Contains(want, "://") {
+		for _, target := range targets {
+			if u, err := url.Parse(target.Address); err == nil && strings.EqualFold(u.Host, want) {
+				return target, nil
+			}
+		}
+	}
+
+	id, err := resolver.Resolve(resolver.KindTarget, value)
+	if err != nil {
+		return store.TargetRecord{}, err
+	}
+	for _, target := range targets {
+		if target.TargetID == id {
+			return target, nil
+		}
+	}
+	return store.TargetRecord{}, fmt.Errorf("target %s has no history, run acucli sync first", value)
+}
+
+// Severity counts of the completed results since the given time, oldest first
+func severitySeries(results []store.ResultSnapshot, since time.Time) []map[string]interface{} {
+	series := []map[string]interface{}{}
+	for _, result := range results {
+		if result.Status != "completed" || result.StartDate.Before(since) {
+			continue
+		}
+		var vulnerabilities []scandata.Vulnerability
+		for _, ref := range result.Findings {
+			vulnerabilities = append(vulnerabilities, scandata.Vulnerability{Severity: ref.Severity})
+		}
+		series = append(series, map[string]interface{}{
+			"date":         result.StartDate.Format(time.RFC3339),
+			"scan_id":      result.ScanID,
+			"result_id":    result.ResultID,
+			"profile_name": result.ProfileName,
+			"counts":       scandata.CountSeverities(vulnerabilities),
+		})
+	}
+	return series
+}
+
+// Mean number of days from detection to fix of the fixes since the given time, per severity and overall
+func meanTimeToFix(findings []store.Finding, since time.Time) map[string]interface{} {
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		for i, fixedAt := range finding.FixedAt {
+			if fixedAt.Before(since) || i >= len(finding.FixDurations) {
+				continue
+			}
+			for _, key := range []string{scandata.SeverityName(finding.Severity), "all"} {
+				totals[key] += finding.FixDurations[i]
+				counts[key]++
+			}
+		}
+	}
+
+	result := make(map[string]interface{})
+	for _, key := range append(append([]string{}, scandata.SeverityNames...), "all") {
+		if counts[key] == 0 {
+			continue
+		}
+		days := totals[key].Hours() / 24 / float64(counts[key])
+		result[key] = map[string]interface{}{
+			"fixed": counts[key],
+			"days":  math.Round(days*10) / 10,
+		}
+	}
+	return result
+}
+
+// Findings which were found again after a fix since the given time
+func reopenedFindings(findings []store.Finding, since time.Time) []map[string]interface{} {
+	reopened := []map[string]interface{}{}
+	for _, finding := range findings {
+		var dates []string
+		for _, date := range finding.Reopened {
+			if !date.Before(since) {
+				dates = append(dates, date.Format(time.RFC3339))
+			}
+		}
+		if len(dates) == 0 {
+			continue
+		}
+		reopened = append(reopened, map[string]interface{}{
+			"fingerprint": finding.Fingerprint,
+			"name":        finding.Name,
+			"severity":    scandata.SeverityName(finding.Severity),
+			"affects_url": finding.AffectsURL,
+			"reopened_at": dates,
+			"open":        finding.Open,
+		})
+	}
+	return reopened
+}
+
+// Severity counts of the findings open in the latest result
+func openCounts(findings []store.Finding) scandata.SeverityCounts {
+	var vulnerabilities []scandata.Vulnerability
+	for _, finding := range findings {
+		if finding.Open {
+			vulnerabilities = append(vulnerabilities, scandata.Vulnerability{Severity: finding.Severity})
+		}
+	}
+	return scandata.CountSeverities(vulnerabilities)
+}
+
+func init() {
+	TrendsCmd.Flags().String("target", "", "Target ID or address")
+	completion.Register(TrendsCmd, resolver.KindTarget, "target")
+	TrendsCmd.Flags().String("since", "90d", "Start of the period: 90d, 12w, 36h or a date (2026-01-01)")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// trendsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// trendsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/vulnformat"
+)
+
+// Requests to DefectDojo use their own client, the API client adds the Acunetix API key to every request
+var defectDojoClient = &http.Client{Timeout: 120 * time.Second}
+
+// importScan holds the fields of a DefectDojo import-scan request
+type importScan struct {
+	Engagement       int
+	ScanType         string
+	ScanDate         string
+	MinimumSeverity  string
+	Active           bool
+	Verified         bool
+	CloseOldFindings bool
+	FileName         string
+	File             []byte
+}
+
+// defectDojoCmd represents the defectdojo command
+var DefectDojoCmd = &cobra.Command{
+	Use:   "defectdojo",
+	Short: "Import the vulnerabilities of a scan into a DefectDojo engagement",
+	Long: `Imports the vulnerabilities of a scan into a DefectDojo engagement with the import-scan API. The DefectDojo URL
+and API token are read from the defectdojo section of the config file. By default the Acunetix XML format is uploaded
+("Acunetix Scan"), --format generic-findings uploads the Generic Findings Import JSON. A file written earlier with
+--format can be uploaded with --file. Example:
+
+acucli push defectdojo --engagement 12 --scan 7f0e3c1a-...
+acucli push defectdojo --engagement 12 --scan 7f0e3c1a-... --format generic-findings --close-old-findings
+acucli push defectdojo --engagement 12 --file findings.xml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		engagement, _ := cmd.Flags().GetInt("engagement")
+		scanFlag, _ := cmd.Flags().GetString("scan")
+		resultID, _ := cmd.Flags().GetString("result")
+		format, _ := cmd.Flags().GetString("format")
+		file, _ := cmd.Flags().GetString("file")
+		minimumSeverity, _ := cmd.Flags().GetString("minimum-severity")
+		active, _ := cmd.Flags().GetBool("active")
+		verified, _ := cmd.Flags().GetBool("verified")
+		closeOld, _ := cmd.Flags().GetBool("close-old-findings")
+
+		if format != vulnformat.FormatDefectDojo && format != vulnformat.FormatGenericFindings {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid format %q (defectdojo or generic-findings)", format), "Error")
+			return
+		}
+		if (scanFlag == "") == (file == "") {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("one of --scan or --file is required"), "Error")
+			return
+		}
+		minimumSeverity, err := severityTitle(minimumSeverity)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+
+		request := importScan{
+			Engagement:       engagement,
+			ScanType:         vulnformat.ScanType(format),
+			ScanDate:         time.Now().Format("2006-01-02"),
+			MinimumSeverity:  minimumSeverity,
+			Active:           active,
+			Verified:         verified,
+			CloseOldFindings: closeOld,
+		}
+
+		if file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error reading file")
+				return
+			}
+			// The file extension tells which format it was written in
+			if strings.HasSuffix(strings.ToLower(file), vulnformat.Extension(vulnformat.FormatDefectDojo)) {
+				request.ScanType = vulnformat.ScanType(vulnformat.FormatDefectDojo)
+			} else {
+				request.ScanType = vulnformat.ScanType(vulnformat.FormatGenericFindings)
+			}
+			request.FileName = filepath.Base(file)
+			request.File = data
+		} else {
+			scanID := scanFlag
+			scan, result, vulnerabilities, err := scandata.LoadResult(scanID, resultID, true)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error getting scan vulnerabilities")
+				return
+			}
+			data, err := vulnformat.Encode(format, []vulnformat.ScanFindings{{
+				Address:         scan.Target.Address,
+				StartDate:       result.StartDate,
+				EndDate:         result.EndDate,
+				Vulnerabilities: vulnerabilities,
+			}})
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error encoding vulnerabilities")
+				return
+			}
+			if len(result.StartDate) >= 10 {
+				request.ScanDate = result.StartDate[:10]
+			}
+			request.FileName = "acunetix-" + scanID + vulnformat.Extension(format)
+			request.File = data
+		}
+
+		statusCode, body := postImportScan(request)
+		if statusCode != http.StatusCreated && statusCode != http.StatusOK {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("status code %d: %s", statusCode, body), "Error importing scan into DefectDojo")
+			return
+		}
+		jsonoutput.OutputRawJSON([]byte(body))
+	},
+}
+
+// Severities accepted by the minimum_severity field of DefectDojo
+var defectDojoSeverities = []string{"Info", "Low", "Medium", "High", "Critical"}
+
+// DefectDojo expects capitalized severities such as "Info" or "High"
+func severityTitle(severity string) (string, error) {
+	severity = strings.TrimSpace(severity)
+	if severity == "" {
+		return "Info", nil
+	}
+	for _, s := range defectDojoSeverities {
+		if strings.EqualFold(s, severity) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid minimum severity %q (%s)", severity, strings.Join(defectDojoSeverities, ", "))
+}
+
+// Upload a scan file to the import-scan endpoint of DefectDojo
+func postImportScan(request importScan) (int, string) {
+	baseURL := strings.TrimSuffix(viper.GetString("defectdojo.url"), "/")
+	token := viper.GetString("defectdojo.token")
+	if baseURL == "" || token == "" {
+		return 500, "defectdojo needs url and token in the config file"
+	}
+
+	var payload bytes.Buffer
+	writer := multipart.NewWriter(&payload)
+	fields := [][2]string{
+		{"engagement", strconv.Itoa(request.Engagement)},
+		{"scan_type", request.ScanType},
+		{"scan_date", request.ScanDate},
+		{"minimum_severity", request.MinimumSeverity},
+		{"active", strconv.FormatBool(request.Active)},
+		{"verified", strconv.FormatBool(request.Verified)},
+		{"close_old_findings", strconv.FormatBool(request.CloseOldFindings)},
+	}
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return 500, fmt.Sprintf("error creating request: %v", err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", request.FileName)
+	if err != nil {
+		return 500, fmt.Sprintf("error creating request: %v", err)
+	}
+	part.Write(request.File)
+	writer.Close()
+
+	req, err := http.NewRequest("POST", baseURL+"/api/v2/import-scan/", &payload)
+	if err != nil {
+		return 500, fmt.Sprintf("error creating request: %v", err)
+	}
+	req.Header.Set("Authorization", "Token "+token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := defectDojoClient.Do(req)
+	if err != nil {
+		return 500, fmt.Sprintf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 500, fmt.Sprintf("error reading response body: %v", err)
+	}
+	if !json.Valid(body) {
+		body, _ = json.Marshal(map[string]string{"response": string(body)})
+	}
+	return resp.StatusCode, string(body)
+}
+
+func init() {
+	DefectDojoCmd.Flags().Int("engagement", 0, "DefectDojo engagement ID to import into")
+	DefectDojoCmd.MarkFlagRequired("engagement")
+	DefectDojoCmd.Flags().String("scan", "", "Scan ID")
+	DefectDojoCmd.Flags().String("result", "", "Result ID of the scan (default is the latest result)")
+	DefectDojoCmd.Flags().String("format", vulnformat.FormatDefectDojo, "Upload format: defectdojo or generic-findings")
+	DefectDojoCmd.Flags().String("file", "", "Upload a file written with --format instead of a scan")
+	DefectDojoCmd.Flags().String("minimum-severity", "Info", "Minimum severity imported: Info, Low, Medium, High or Critical")
+	DefectDojoCmd.Flags().Bool("active", true, "Mark imported findings as active")
+	DefectDojoCmd.Flags().Bool("verified", false, "Mark imported findings as verified")
+	DefectDojoCmd.Flags().Bool("close-old-findings", false, "Close findings of the engagement that are not in this import")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// defectDojoCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// defectDojoCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
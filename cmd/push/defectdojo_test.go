@@ -0,0 +1,107 @@
+package push
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestPostImportScan(t *testing.T) {
+	var got *http.Request
+	var file string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parsing multipart form: %v", err)
+		}
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("reading file part: %v", err)
+		} else {
+			content, _ := io.ReadAll(f)
+			file = header.Filename + ":" + string(content)
+			f.Close()
+		}
+		got = r
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"test": 7}`))
+	}))
+	defer srv.Close()
+	viper.Set("defectdojo.url", srv.URL+"/")
+	viper.Set("defectdojo.token", "secret")
+	defer viper.Set("defectdojo.url", "")
+
+	status, body := postImportScan(importScan{
+		Engagement:       12,
+		ScanType:         "Acunetix Scan",
+		ScanDate:         "2024-05-01",
+		MinimumSeverity:  "High",
+		Active:           true,
+		CloseOldFindings: true,
+		FileName:         "findings.xml",
+		File:             []byte("<ScanGroup/>"),
+	})
+	if status != http.StatusCreated || body != `{"test": 7}` {
+		t.Fatalf("got %d %s, want 201 with the response body", status, body)
+	}
+	if got.Method != "POST" || got.URL.Path != "/api/v2/import-scan/" {
+		t.Errorf("got %s %s, want POST /api/v2/import-scan/", got.Method, got.URL.Path)
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Token secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "Token secret")
+	}
+	fields := map[string]string{
+		"engagement":         "12",
+		"scan_type":          "Acunetix Scan",
+		"scan_date":          "2024-05-01",
+		"minimum_severity":   "High",
+		"active":             "true",
+		"verified":           "false",
+		"close_old_findings": "true",
+	}
+	for name, want := range fields {
+		if value := got.FormValue(name); value != want {
+			t.Errorf("%s = %q, want %q", name, value, want)
+		}
+	}
+	if file != "findings.xml:<ScanGroup/>" {
+		t.Errorf("file part = %q, want findings.xml:<ScanGroup/>", file)
+	}
+}
+
+func TestPostImportScanError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Engagement not found"))
+	}))
+	defer srv.Close()
+	viper.Set("defectdojo.url", srv.URL)
+	viper.Set("defectdojo.token", "secret")
+	defer viper.Set("defectdojo.url", "")
+
+	status, body := postImportScan(importScan{Engagement: 99, FileName: "findings.xml"})
+	if status != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", status)
+	}
+	if body != `{"response":"Engagement not found"}` {
+		t.Errorf("body = %s, want the text response wrapped in JSON", body)
+	}
+
+	viper.Set("defectdojo.url", "")
+	if status, _ := postImportScan(importScan{}); status != 500 {
+		t.Errorf("status without url = %d, want 500", status)
+	}
+}
+
+func TestSeverityTitle(t *testing.T) {
+	for _, c := range []struct{ in, want string }{{"", "Info"}, {"critical", "Critical"}, {" HIGH ", "High"}} {
+		if got, err := severityTitle(c.in); err != nil || got != c.want {
+			t.Errorf("severityTitle(%q) = %q, %v, want %q", c.in, got, err, c.want)
+		}
+	}
+	if _, err := severityTitle("severe"); err == nil {
+		t.Error("severityTitle(\"severe\") did not fail")
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package push
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// PushCmd represents the push command
+var PushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push scan results to vulnerability management platforms",
+	Long: `Pushes the vulnerabilities of a scan to a vulnerability management platform. Example:
+
+acucli push defectdojo --engagement 12 --scan 7f0e3c1a-...`,
+}
+
+func init() {
+	PushCmd.AddCommand(DefectDojoCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// pushCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// pushCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
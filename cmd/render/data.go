@@ -0,0 +1,105 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package render
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// ReportData is the data passed to the templates
+type ReportData struct {
+	Scan            scandata.Scan
+	Result          scandata.Result
+	Vulnerabilities []scandata.Vulnerability
+	Groups          []SeverityGroup
+	Issues          []Issue
+	Counts          scandata.SeverityCounts
+	Technologies    []scandata.Technology
+	GeneratedAt     time.Time
+}
+
+// SeverityGroup holds the vulnerabilities of one severity
+type SeverityGroup struct {
+	Severity        string
+	Vulnerabilities []scandata.Vulnerability
+}
+
+// Issue is a vulnerability type with every location it was found at
+type Issue struct {
+	VtID     string
+	Name     string
+	Severity string
+	Count    int
+	URLs     []string
+	Example  scandata.Vulnerability
+}
+
+// Fetch the scan, result, vulnerabilities and technologies, with details fetches
+// the description and recommendation of every vulnerability
+func loadReportData(scanID string, resultID string, details bool) (ReportData, error) {
+	data := ReportData{GeneratedAt: time.Now()}
+
+	scan, result, vulnerabilities, err := scandata.LoadResult(scanID, resultID, details)
+	if err != nil {
+		return data, err
+	}
+	data.Scan = scan
+	data.Result = result
+	data.Vulnerabilities = vulnerabilities
+	data.Counts = scandata.CountSeverities(vulnerabilities)
+	data.Groups = groupBySeverity(vulnerabilities)
+	data.Issues = groupByType(vulnerabilities)
+
+	data.Technologies, err = scandata.FetchTechnologies(scanID, result.ResultID)
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// Group sorted vulnerabilities by severity, leaving out empty severities
+func groupBySeverity(vulnerabilities []scandata.Vulnerability) []SeverityGroup {
+	var groups []SeverityGroup
+	for _, v := range vulnerabilities {
+		name := v.SeverityName()
+		if len(groups) == 0 || groups[len(groups)-1].Severity != name {
+			groups = append(groups, SeverityGroup{Severity: name})
+		}
+		groups[len(groups)-1].Vulnerabilities = append(groups[len(groups)-1].Vulnerabilities, v)
+	}
+	return groups
+}
+
+// Group sorted vulnerabilities by vulnerability type, most severe and most frequent first
+func groupByType(vulnerabilities []scandata.Vulnerability) []Issue {
+	var issues []Issue
+	index := make(map[string]int)
+	for _, v := range vulnerabilities {
+		key := v.VtID
+		if key == "" {
+			key = v.Name
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(issues)
+			index[key] = i
+			issues = append(issues, Issue{VtID: v.VtID, Name: v.Name, Severity: v.SeverityName(), Example: v})
+		}
+		issues[i].Count++
+		issues[i].URLs = append(issues[i].URLs, v.AffectsURL)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].Example.Severity, issues[j].Example.Severity
+		if a != b {
+			return a > b
+		}
+		return issues[i].Count > issues[j].Count
+	})
+	return issues
+}
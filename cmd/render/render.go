@@ -0,0 +1,207 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package render
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// RenderCmd represents the render command
+var RenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a report of a scan locally from a Go template",
+	Long: `Fetches the scan, its result, vulnerabilities and technologies and renders them with a Go template, without the
+server's report engine. Templates whose name ends in .html or .htm (optionally followed by .tmpl) are rendered with
+html/template, others with text/template. Built-in templates:
+
+  executive   HTML summary for management
+  developer   Markdown with the description and fix of every vulnerability
+  pr-comment  Short Markdown table for pull request comments
+
+Example:
+
+acucli render --scan <SCAN-ID> --template executive -o report.html
+acucli render --scan <SCAN-ID> --template my.md.tmpl > report.md
+acucli render --scan <SCAN-ID> --template pr-comment --no-details | gh pr comment 42 --body-file -`,
+	Run: func(cmd *cobra.Command, args []string) {
+		scanID, _ := cmd.Flags().GetString("scan")
+		resultID, _ := cmd.Flags().GetString("result")
+		templateName, _ := cmd.Flags().GetString("template")
+		output, _ := cmd.Flags().GetString("output")
+		noDetails, _ := cmd.Flags().GetBool("no-details")
+
+		if scanID == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan ID is required"), "Error")
+			return
+		}
+
+		name, source, err := loadTemplate(templateName)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error loading template")
+			return
+		}
+
+		data, err := loadReportData(scanID, resultID, !noDetails)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting scan data")
+			return
+		}
+
+		rendered, err := renderTemplate(name, source, data)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error rendering template")
+			return
+		}
+
+		if output == "" {
+			os.Stdout.Write(rendered)
+			return
+		}
+
+		err = os.WriteFile(output, rendered, 0644)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error writing report")
+			return
+		}
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"status":          "success",
+			"file_path":       output,
+			"scan_id":         scanID,
+			"result_id":       data.Result.ResultID,
+			"vulnerabilities": data.Counts,
+		})
+	},
+}
+
+// Load a template from a file, or else a built-in template by name
+func loadTemplate(name string) (string, []byte, error) {
+	if source, err := os.ReadFile(name); err == nil {
+		return filepath.Base(name), source, nil
+	} else if !os.IsNotExist(err) {
+		return "", nil, err
+	}
+
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return "", nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		builtin := strings.SplitN(entry.Name(), ".", 2)[0]
+		if builtin == name {
+			source, err := builtinTemplates.ReadFile("templates/" + entry.Name())
+			return entry.Name(), source, err
+		}
+		names = append(names, builtin)
+	}
+	sort.Strings(names)
+	return "", nil, fmt.Errorf("template %q is neither a file nor a built-in template (%s)", name, strings.Join(names, ", "))
+}
+
+// completeTemplate offers the built-in templates, falling back to file names for custom ones
+func completeTemplate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	var names []string
+	for _, entry := range entries {
+		builtin := strings.SplitN(entry.Name(), ".", 2)[0]
+		if strings.HasPrefix(builtin, toComplete) {
+			names = append(names, builtin+"\tbuilt-in template")
+		}
+	}
+	return names, cobra.ShellCompDirectiveDefault
+}
+
+// HTML templates are recognised by their extension, the .tmpl suffix is ignored
+func isHTMLTemplate(name string) bool {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(name, ".tmpl")))
+	return ext == ".html" || ext == ".htm"
+}
+
+func renderTemplate(name string, source []byte, data ReportData) ([]byte, error) {
+	var buf bytes.Buffer
+	if isHTMLTemplate(name) {
+		funcs := htmltemplate.FuncMap(templateFuncs())
+		funcs["safeHTML"] = func(s string) htmltemplate.HTML { return htmltemplate.HTML(s) }
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(source))
+		if err != nil {
+			return nil, err
+		}
+		err = tmpl.Execute(&buf, data)
+		return buf.Bytes(), err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(string(source))
+	if err != nil {
+		return nil, err
+	}
+	err = tmpl.Execute(&buf, data)
+	return buf.Bytes(), err
+}
+
+// Functions available in every template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"join":      strings.Join,
+		"severity":  scandata.SeverityName,
+		"add":       func(a, b int) int { return a + b },
+		"stripTags": scandata.PlainText,
+		"truncate": func(n int, s string) string {
+			runes := []rune(s)
+			if len(runes) <= n {
+				return s
+			}
+			return string(runes[:n]) + "…"
+		},
+		// Escape the characters which break Markdown tables and emphasis
+		"mdEscape": func(s string) string {
+			return strings.NewReplacer("|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`", "\n", " ", "\r", "").Replace(s)
+		},
+		// Format a server date (RFC 3339) with a Go layout, unparsable dates are returned as is
+		"date": func(layout string, value string) string {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return value
+			}
+			return t.Format(layout)
+		},
+	}
+}
+
+func init() {
+	RenderCmd.Flags().String("scan", "", "Scan ID")
+	RenderCmd.Flags().String("result", "", "Result ID (default is the latest result of the scan)")
+	RenderCmd.Flags().StringP("template", "t", "executive", "Template file or built-in template: executive, developer or pr-comment")
+	RenderCmd.Flags().StringP("output", "o", "", "File to write the report to (default is stdout)")
+	RenderCmd.Flags().Bool("no-details", false, "Skip fetching the description and recommendation of every vulnerability")
+	RenderCmd.RegisterFlagCompletionFunc("template", completeTemplate)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// renderCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// renderCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -12,9 +12,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type ReportSource struct {
@@ -35,18 +37,16 @@ var GenerateCmd = &cobra.Command{
 	Long: `Generate a new report based on scan IDs. Takes scan IDs from stdin. Example:
 
 echo "scan_id_here" | acucli report generate --template=11111111-1111-1111-1111-111111111126 --description="My Report" --list-type=all_vulnerabilities
+echo "scan_id_here" | acucli report generate --template="Executive Summary"
 
 Available list types:
 - all_vulnerabilities (default)
 - open_vulnerabilities
 - fixed_vulnerabilities
 
-Available template IDs:
-- 11111111-1111-1111-1111-111111111126 (Comprehensive)
-- 11111111-1111-1111-1111-111111111111 (Developer)
-- 11111111-1111-1111-1111-111111111112 (Executive Summary)
-- 11111111-1111-1111-1111-111111111113 (Quick)
-- 11111111-1111-1111-1111-111111111124 (OWASP Top 10 2021)`,
+The --template flag accepts a template ID or a template name (case-insensitive).
+Run "acucli report templates" to list the templates available on the server.
+Defaults to the Comprehensive template (11111111-1111-1111-1111-111111111126).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		input := filehelper.ReadStdin()
 		if input == nil || len(input) == 0 {
@@ -60,6 +60,12 @@ Available template IDs:
 			templateID = "11111111-1111-1111-1111-111111111126"
 		}
 
+		templateID, err := ResolveTemplateID(templateID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving report template")
+			return
+		}
+
 		description, _ := cmd.Flags().GetString("description")
 		if description == "" {
 			description = "Report generated by acucli"
@@ -70,63 +76,73 @@ Available template IDs:
 			listType = "all_vulnerabilities"
 		}
 
-		generateReport(templateID, description, listType, input)
+		GenerateReport(templateID, description, listType, input)
 	},
 }
 
-func generateReport(templateID, description, listType string, scanIDs []string) {
+// GenerateReport creates a report of the given sources and outputs the response
+func GenerateReport(templateID, description, listType string, scanIDs []string) {
+	_, body, err := RequestReport(templateID, description, listType, scanIDs)
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error generating report")
+		return
+	}
+
+	// Check if the response is valid JSON
+	var responseBody interface{}
+	err = json.Unmarshal([]byte(body), &responseBody)
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON")
+		return
+	}
+
+	// Output only the JSON response
+	jsonoutput.OutputRawJSON([]byte(body))
+}
+
+// RequestReport creates a report of the given sources and returns the status code and response body,
+// the error is set when the request could not be made
+func RequestReport(templateID, description, listType string, ids []string) (int, string, error) {
 	reportRequest := ReportRequest{
 		TemplateID: templateID,
 		Source: ReportSource{
 			Description: description,
 			ListType:    listType,
-			IDList:      scanIDs,
+			IDList:      ids,
 		},
 	}
 
 	requestJson, err := json.Marshal(reportRequest)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
-		return
+		return 0, "", fmt.Errorf("error creating JSON request: %v", err)
 	}
 
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/reports"), bytes.NewBuffer(requestJson))
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating request")
-		return
+		return 0, "", fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// Perform the request using the custom client
 	resp, err := httpclient.MyHTTPClient.Do(req)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error making request")
-		return
+		return 0, "", fmt.Errorf("error making request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
-		return
-	}
-
-	// Check if the response is valid JSON
-	var responseBody interface{}
-	err = json.Unmarshal(body, &responseBody)
-	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON")
-		return
+		return resp.StatusCode, "", fmt.Errorf("error reading response body: %v", err)
 	}
 
-	// Output only the JSON response
-	jsonoutput.OutputRawJSON(body)
+	return resp.StatusCode, string(body), nil
 }
 
 func init() {
-	GenerateCmd.Flags().StringP("template", "t", "11111111-1111-1111-1111-111111111126", "Report template ID")
+	GenerateCmd.Flags().StringP("template", "t", "11111111-1111-1111-1111-111111111126", "Report template ID or name")
 	GenerateCmd.Flags().StringP("description", "d", "Report generated by acucli", "Report description")
 	GenerateCmd.Flags().StringP("list-type", "l", "all_vulnerabilities", "List type (all_vulnerabilities, open_vulnerabilities, fixed_vulnerabilities)")
+	completion.Register(GenerateCmd, resolver.KindReportTemplate, "template")
 
 	// Here you will define your flags and configuration settings.
 
@@ -41,47 +41,55 @@ cat report_ids.txt | acucli report remove : Removes multiple reports`,
 }
 
 func removeReports(reportIDs []string) {
+	_, body, err := RemoveReports(reportIDs)
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error removing reports")
+		return
+	}
+
+	// Check if the response is valid JSON
+	var responseBody interface{}
+	err = json.Unmarshal([]byte(body), &responseBody)
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON")
+		return
+	}
+
+	// Output only the JSON response
+	jsonoutput.OutputRawJSON([]byte(body))
+}
+
+// RemoveReports deletes reports and returns the status code and response body, the error is set
+// when the request could not be made
+func RemoveReports(reportIDs []string) (int, string, error) {
 	request := RemoveReportRequest{
 		ReportIDList: reportIDs,
 	}
 
 	requestJson, err := json.Marshal(request)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
-		return
+		return 0, "", fmt.Errorf("error creating JSON request: %v", err)
 	}
 
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/reports/delete"), bytes.NewBuffer(requestJson))
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating request")
-		return
+		return 0, "", fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// Perform the request using the custom client
 	resp, err := httpclient.MyHTTPClient.Do(req)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error making request")
-		return
+		return 0, "", fmt.Errorf("error making request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
-		return
+		return resp.StatusCode, "", fmt.Errorf("error reading response body: %v", err)
 	}
 
-	// Check if the response is valid JSON
-	var responseBody interface{}
-	err = json.Unmarshal(body, &responseBody)
-	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON")
-		return
-	}
-
-	// Output only the JSON response
-	jsonoutput.OutputRawJSON(body)
+	return resp.StatusCode, string(body), nil
 }
 
 func init() {
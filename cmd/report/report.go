@@ -11,7 +11,7 @@ import (
 var ReportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Commands for managing reports",
-	Long:  `Commands for managing reports, including listing, getting, and generating reports.`,
+	Long:  `Commands for managing reports, including listing, getting, and generating reports and listing report templates.`,
 }
 
 func init() {
@@ -20,6 +20,7 @@ func init() {
 	ReportCmd.AddCommand(GenerateCmd)
 	ReportCmd.AddCommand(RemoveCmd)
 	ReportCmd.AddCommand(GetCmd)
+	ReportCmd.AddCommand(TemplatesCmd)
 
 	// Here you will define your flags and configuration settings.
 
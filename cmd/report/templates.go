@@ -0,0 +1,69 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+type ReportTemplate struct {
+	TemplateID      string   `json:"template_id"`
+	Name            string   `json:"name"`
+	Group           string   `json:"group"`
+	AcceptedSources []string `json:"accepted_sources"`
+}
+
+type ReportTemplates struct {
+	Templates []ReportTemplate `json:"templates"`
+}
+
+// TemplatesCmd represents the templates command
+var TemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List report templates",
+	Long: `Lists the report templates available on the server. The template ID or the name can be used with the --template flag. Example:
+
+acucli report templates`,
+	Run: func(cmd *cobra.Command, args []string) {
+		body, err := httpclient.Get("/report_templates")
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting report templates")
+			return
+		}
+
+		// Check if the response is valid JSON
+		var templates ReportTemplates
+		err = json.Unmarshal(body, &templates)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error parsing JSON")
+			return
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputRawJSON(body)
+	},
+}
+
+// ResolveTemplateID returns the template ID for the given UUID or template name.
+// Names are matched case-insensitively against the server's template list.
+func ResolveTemplateID(template string) (string, error) {
+	return resolver.Resolve(resolver.KindReportTemplate, template)
+}
+
+func init() {
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// TemplatesCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// TemplatesCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
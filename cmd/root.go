@@ -10,13 +10,23 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tosbaa/acucli/cmd/auto"
+	"github.com/tosbaa/acucli/cmd/excludedHours"
 	"github.com/tosbaa/acucli/cmd/export"
+	"github.com/tosbaa/acucli/cmd/exporter"
+	"github.com/tosbaa/acucli/cmd/history"
+	"github.com/tosbaa/acucli/cmd/push"
+	"github.com/tosbaa/acucli/cmd/render"
 	"github.com/tosbaa/acucli/cmd/report"
 	"github.com/tosbaa/acucli/cmd/scan"
 	"github.com/tosbaa/acucli/cmd/scanProfile"
+	"github.com/tosbaa/acucli/cmd/serve"
 	"github.com/tosbaa/acucli/cmd/target"
 	"github.com/tosbaa/acucli/cmd/targetGroup"
+	"github.com/tosbaa/acucli/cmd/tui"
+	"github.com/tosbaa/acucli/cmd/vulnerability"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 var (
@@ -26,6 +36,8 @@ var (
 	waitTimeout  int
 	outputPath   string
 	outputFormat string
+	templateID   string
+	failOn       string
 	autoMode     bool
 	versionFlag  bool
 )
@@ -42,6 +54,10 @@ var RootCmd = &cobra.Command{
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Completion works without a config, dynamic completions load it themselves once flags are parsed
+		if cmd == completionCmd || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+			return nil
+		}
 		return initConfig()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -54,7 +70,7 @@ var RootCmd = &cobra.Command{
 			if targetURL == "" {
 				return fmt.Errorf("target URL is required when using auto mode")
 			}
-			return auto.RunAutoCommand(targetURL, waitTimeout, outputPath, outputFormat)
+			return auto.RunAutoCommand(targetURL, waitTimeout, outputPath, outputFormat, templateID, failOn)
 		}
 
 		return cmd.Help()
@@ -78,6 +94,18 @@ func init() {
 	RootCmd.AddCommand(scan.ScanCmd)
 	RootCmd.AddCommand(report.ReportCmd)
 	RootCmd.AddCommand(export.ExportCmd)
+	RootCmd.AddCommand(excludedHours.ExcludedHoursCmd)
+	RootCmd.AddCommand(render.RenderCmd)
+	RootCmd.AddCommand(history.SyncCmd)
+	RootCmd.AddCommand(history.TrendsCmd)
+	RootCmd.AddCommand(vulnerability.VulnerabilityCmd)
+	RootCmd.AddCommand(push.PushCmd)
+	RootCmd.AddCommand(serve.ServeCmd)
+	RootCmd.AddCommand(exporter.ExporterCmd)
+	RootCmd.AddCommand(tui.TuiCmd)
+	RootCmd.AddCommand(completionCmd)
+	RootCmd.CompletionOptions.DisableDefaultCmd = true
+	completion.LoadConfig = initConfig
 
 	// Global flags
 	RootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $HOME/.acucli.yaml)")
@@ -87,7 +115,10 @@ func init() {
 	RootCmd.Flags().StringVarP(&targetURL, "u", "u", "", "Target URL to scan")
 	RootCmd.Flags().IntVarP(&waitTimeout, "i", "i", 800, "Timeout in seconds for waiting operations")
 	RootCmd.Flags().StringVarP(&outputPath, "o", "o", "", "Output path for downloaded report files")
-	RootCmd.Flags().StringVarP(&outputFormat, "f", "f", "html", "Output format (csv or html)")
+	RootCmd.Flags().StringVarP(&outputFormat, "f", "f", "html", "Output format (html, csv, defectdojo or generic-findings)")
+	RootCmd.Flags().StringVarP(&templateID, "reportTemplateID", "r", "", "Report template ID or name (html format only)")
+	completion.Register(RootCmd, resolver.KindReportTemplate, "reportTemplateID")
+	RootCmd.Flags().StringVar(&failOn, "fail-on", "", "Fail when the scan has a vulnerability of this severity or above: critical, high, medium or low")
 	RootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "Show version information")
 }
 
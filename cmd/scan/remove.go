@@ -33,7 +33,7 @@ cat scan_ids.txt | acucli scan remove : Removes multiple scans`,
 
 		results := make(map[string]interface{})
 		for _, scanID := range input {
-			statusCode, responseBody := removeScan(scanID)
+			statusCode, responseBody := RemoveScan(scanID)
 			results[scanID] = map[string]interface{}{
 				"status_code": statusCode,
 				"response":    responseBody,
@@ -45,7 +45,8 @@ cat scan_ids.txt | acucli scan remove : Removes multiple scans`,
 	},
 }
 
-func removeScan(scanID string) (int, string) {
+// RemoveScan deletes a scan and returns the status code and response body
+func RemoveScan(scanID string) (int, string) {
 	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/scans/%s", viper.GetString("URL"), scanID), nil)
 	if err != nil {
 		return 500, fmt.Sprintf("Error creating request: %v", err)
@@ -12,9 +12,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type postBody struct {
@@ -40,7 +41,11 @@ var ScanCmd = &cobra.Command{
 
 cat target_ids.txt | acucli scan --scanProfileID=47973ea9-018b-4294-9903-bb1cf3b1e886`,
 	Run: func(cmd *cobra.Command, args []string) {
-		targets := filehelper.ReadStdin()
+		targets, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
 		if targets == nil || len(targets) == 0 {
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
 			return
@@ -51,10 +56,15 @@ cat target_ids.txt | acucli scan --scanProfileID=47973ea9-018b-4294-9903-bb1cf3b
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan profile ID is required"), "Error")
 			return
 		}
+		scanProfileID, err = resolver.Resolve(resolver.KindScanProfile, scanProfileID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profile")
+			return
+		}
 
 		results := make(map[string]interface{})
 		for _, target := range targets {
-			statusCode, responseBody := startScan(target, scanProfileID)
+			statusCode, responseBody := StartScan(target, scanProfileID)
 			results[target] = map[string]interface{}{
 				"status_code": statusCode,
 				"response":    responseBody,
@@ -66,7 +76,8 @@ cat target_ids.txt | acucli scan --scanProfileID=47973ea9-018b-4294-9903-bb1cf3b
 	},
 }
 
-func startScan(targetID string, scanProfileID string) (int, string) {
+// StartScan starts a scan of a target with the given scan profile and returns the status code and response body
+func StartScan(targetID string, scanProfileID string) (int, string) {
 	postBody := postBody{ProfileID: scanProfileID, Incremental: false, Schedule: Schedule{Disable: false, TimeSensitive: false, StartDate: nil}}
 	postBody.TargetID = targetID
 
@@ -95,9 +106,31 @@ func startScan(targetID string, scanProfileID string) (int, string) {
 	return resp.StatusCode, string(body)
 }
 
+// AbortScan stops a running scan and returns the status code and response body
+func AbortScan(scanID string) (int, string) {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/scans/%s/abort", viper.GetString("URL"), scanID), nil)
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return 500, fmt.Sprintf("Error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Sprintf("Error reading response body: %v", err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
 func init() {
-	ScanCmd.Flags().StringVarP(&scanProfileId, "scanProfileID", "", "", "scanProfile ID")
+	ScanCmd.Flags().StringVarP(&scanProfileId, "scanProfileID", "", "", "scanProfile ID or name")
 	ScanCmd.MarkFlagRequired("scanProfileID")
+	completion.Register(ScanCmd, resolver.KindScanProfile, "scanProfileID")
 
 	ScanCmd.AddCommand(ListCmd)
 	ScanCmd.AddCommand(GetCmd)
@@ -105,6 +138,7 @@ func init() {
 	ScanCmd.AddCommand(ResultsCmd)
 	ScanCmd.AddCommand(VulnerabilitiesCmd)
 	ScanCmd.AddCommand(TechnologiesCmd)
+	ScanCmd.AddCommand(WatchCmd)
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -15,6 +16,8 @@ import (
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/vulnformat"
 )
 
 // vulnerabilitiesCmd represents the vulnerabilities command
@@ -26,7 +29,11 @@ var VulnerabilitiesCmd = &cobra.Command{
 echo "scan_id:result_id" | acucli scan vulnerabilities
 
 You can also pipe the output from the results command and extract the result_id:
-echo "scan_id" | acucli scan results | jq -r '.results[0].scan_id + ":" + .results[0].result_id' | acucli scan vulnerabilities`,
+echo "scan_id" | acucli scan results | jq -r '.results[0].scan_id + ":" + .results[0].result_id' | acucli scan vulnerabilities
+
+For vulnerability management tools, --format defectdojo writes the Acunetix XML format ("Acunetix Scan" in DefectDojo)
+and --format generic-findings the DefectDojo Generic Findings Import JSON:
+echo "scan_id:result_id" | acucli scan vulnerabilities --format defectdojo > findings.xml`,
 	Run: func(cmd *cobra.Command, args []string) {
 		input := filehelper.ReadStdin()
 		if input == nil || len(input) == 0 {
@@ -43,10 +50,41 @@ echo "scan_id" | acucli scan results | jq -r '.results[0].scan_id + ":" + .resul
 
 		scanID := parts[0]
 		resultID := parts[1]
+
+		format, _ := cmd.Flags().GetString("format")
+		if !vulnformat.Valid(format) {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid format %q (%s)", format, strings.Join(vulnformat.Formats, ", ")), "Error")
+			return
+		}
+		if format != vulnformat.FormatJSON {
+			outputScanFindings(scanID, resultID, format)
+			return
+		}
 		getScanVulnerabilities(scanID, resultID)
 	},
 }
 
+// Output the vulnerabilities of a scan result with their details in a vulnerability management format
+func outputScanFindings(scanID, resultID, format string) {
+	scan, result, vulnerabilities, err := scandata.LoadResult(scanID, resultID, true)
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error getting vulnerabilities")
+		return
+	}
+
+	data, err := vulnformat.Encode(format, []vulnformat.ScanFindings{{
+		Address:         scan.Target.Address,
+		StartDate:       result.StartDate,
+		EndDate:         result.EndDate,
+		Vulnerabilities: vulnerabilities,
+	}})
+	if err != nil {
+		jsonoutput.OutputErrorAsJSON(err, "Error encoding vulnerabilities")
+		return
+	}
+	os.Stdout.Write(data)
+}
+
 func getScanVulnerabilities(scanID, resultID string) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/scans/%s/results/%s/vulnerabilities", viper.GetString("URL"), scanID, resultID), nil)
 	if err != nil {
@@ -81,6 +119,8 @@ func getScanVulnerabilities(scanID, resultID string) {
 }
 
 func init() {
+	VulnerabilitiesCmd.Flags().String("format", "json", "Output format: json, defectdojo or generic-findings")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -0,0 +1,162 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/filehelper"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/notify"
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Statuses of scans which will not change anymore
+var finishedStatuses = map[string]bool{
+	"completed": true,
+	"failed":    true,
+	"aborted":   true,
+}
+
+// watchCmd represents the watch command
+var WatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Wait for scans to finish and send notifications",
+	Long: `Waits for the scans taken from stdin, or for every running scan, to finish. For every finished scan the
+notifications configured in the config file are sent (scan_completed, scan_failed, gate_failed, new_critical).
+With --fail-on the command fails when a scan has a vulnerability of that severity or above. Example:
+
+echo "<SCAN-ID>" | acucli scan watch --fail-on high
+acucli scan watch --running --interval 60`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		running, _ := cmd.Flags().GetBool("running")
+		interval, _ := cmd.Flags().GetInt("interval")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+
+		gate, err := notify.ParseGate(failOn)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return nil
+		}
+		if interval < 1 {
+			interval = 1
+		}
+
+		var scanIDs []string
+		if running {
+			scans, err := scandata.FetchScans()
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error listing scans")
+				return nil
+			}
+			for _, scan := range scans {
+				if !finishedStatuses[scan.CurrentSession.Status] {
+					scanIDs = append(scanIDs, scan.ScanID)
+				}
+			}
+		} else {
+			for _, line := range filehelper.ReadStdin() {
+				if id := strings.TrimSpace(line); id != "" {
+					scanIDs = append(scanIDs, id)
+				}
+			}
+		}
+		if len(scanIDs) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no scans to watch"), "Error")
+			return nil
+		}
+
+		failed := watchScans(scanIDs, gate, time.Duration(interval)*time.Second, time.Duration(timeout)*time.Second)
+		if len(failed) > 0 {
+			return fmt.Errorf("gate failed for scans: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+// Poll the scans until they finish or the timeout (0 for none) passes, outputting every finished
+// scan as it finishes. Returns the scans which failed the gate.
+func watchScans(scanIDs []string, gate int, interval time.Duration, timeout time.Duration) []string {
+	pending := make(map[string]bool)
+	for _, id := range scanIDs {
+		pending[id] = true
+	}
+
+	var gateFailed []string
+	start := time.Now()
+	for {
+		for _, id := range scanIDs {
+			if !pending[id] {
+				continue
+			}
+
+			scan, err := scandata.FetchScan(id)
+			if err != nil {
+				delete(pending, id)
+				jsonoutput.OutputJSON(map[string]interface{}{"scan_id": id, "status": "error", "error": err.Error()})
+				continue
+			}
+			if !finishedStatuses[scan.CurrentSession.Status] {
+				continue
+			}
+			delete(pending, id)
+
+			outcome, err := notify.FinishedScanEvents(id, gate)
+			if err != nil {
+				jsonoutput.OutputJSON(map[string]interface{}{"scan_id": id, "status": "error", "error": err.Error()})
+				continue
+			}
+			if outcome.GateFailed {
+				gateFailed = append(gateFailed, id)
+			}
+
+			output := map[string]interface{}{
+				"scan_id":       id,
+				"address":       outcome.Scan.Target.Address,
+				"status":        outcome.Scan.CurrentSession.Status,
+				"counts":        outcome.Counts,
+				"gate_failed":   outcome.GateFailed,
+				"notifications": notify.Dispatch(outcome.Events),
+			}
+			if len(outcome.Warnings) > 0 {
+				output["warnings"] = outcome.Warnings
+			}
+			jsonoutput.OutputJSON(output)
+		}
+
+		if len(pending) == 0 {
+			return gateFailed
+		}
+		if timeout > 0 && time.Since(start) > timeout {
+			for _, id := range scanIDs {
+				if pending[id] {
+					jsonoutput.OutputJSON(map[string]interface{}{"scan_id": id, "status": "timeout"})
+				}
+			}
+			return gateFailed
+		}
+		time.Sleep(interval)
+	}
+}
+
+func init() {
+	WatchCmd.Flags().Bool("running", false, "Watch every scan which is not finished instead of stdin")
+	WatchCmd.Flags().Int("interval", 30, "Seconds between status checks")
+	WatchCmd.Flags().Int("timeout", 0, "Seconds to wait before giving up, 0 waits until every scan finished")
+	WatchCmd.Flags().String("fail-on", "", "Fail when a scan has a vulnerability of this severity or above: critical, high, medium or low")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// watchCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// watchCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
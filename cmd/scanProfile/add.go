@@ -16,6 +16,7 @@ import (
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 // addCmd represents the add command
@@ -76,6 +77,9 @@ func makeRequest(scanProfile ScanProfile) {
 		jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
 		return
 	}
+	if resp.StatusCode < 300 {
+		resolver.Invalidate(resolver.KindScanProfile)
+	}
 
 	// Create a response object
 	response := map[string]interface{}{
@@ -0,0 +1,221 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scanProfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"gopkg.in/yaml.v3"
+)
+
+// applyCmd represents the apply command
+var ApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create or update custom scan profiles from a directory",
+	Long: `Reads the .json, .yaml and .yml scan profile files of a directory (as written by "scanProfile export-all") and
+matches them to the server's profiles by name. Existing custom profiles are updated in place, missing ones are created.
+Files named like a built-in profile are skipped. Example:
+
+acucli scanProfile apply --dir profiles/
+acucli scanProfile apply --dir profiles/ --dry-run : Shows what would be created or updated`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		files, err := readProfileFiles(dir)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading scan profiles")
+			return
+		}
+		if len(files) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no scan profile files found in %s", dir), "Error")
+			return
+		}
+
+		profiles, err := fetchScanProfiles()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing scan profiles")
+			return
+		}
+
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		results := make(map[string]interface{})
+		created := false
+		for _, path := range paths {
+			result := applyProfileFile(files[path], profiles, dryRun)
+			if result["status"] == "created" {
+				created = true
+			}
+			results[path] = result
+		}
+		if created {
+			resolver.Invalidate(resolver.KindScanProfile)
+		}
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"dry_run": dryRun,
+			"results": results,
+		})
+	},
+}
+
+// Read every scan profile file of a directory, names must be unique
+func readProfileFiles(dir string) (map[string]profileFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]profileFile)
+	names := make(map[string]string)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var profile profileFile
+		if ext == ".json" {
+			err = json.Unmarshal(data, &profile)
+		} else {
+			err = yaml.Unmarshal(data, &profile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", path, err)
+		}
+		if strings.TrimSpace(profile.Name) == "" {
+			return nil, fmt.Errorf("%s has no name", path)
+		}
+
+		key := strings.ToLower(profile.Name)
+		if other, ok := names[key]; ok {
+			return nil, fmt.Errorf("scan profile %q is defined in both %s and %s", profile.Name, other, path)
+		}
+		names[key] = path
+		files[path] = profile
+	}
+
+	return files, nil
+}
+
+// Create or update the server profile matching the file by name
+func applyProfileFile(file profileFile, profiles []ScanProfile, dryRun bool) map[string]interface{} {
+	checks := sortedChecks(file.Checks)
+
+	var existing *ScanProfile
+	for i := range profiles {
+		if strings.EqualFold(profiles[i].Name, file.Name) {
+			existing = &profiles[i]
+			break
+		}
+	}
+
+	if existing == nil {
+		if dryRun {
+			return map[string]interface{}{"name": file.Name, "status": "would create"}
+		}
+		statusCode, body := createScanProfile(ScanProfile{Name: file.Name, Custom: true, Checks: checks, SortOrder: file.SortOrder})
+		status := "created"
+		if statusCode >= 300 {
+			status = "error"
+		}
+		return map[string]interface{}{"name": file.Name, "status": status, "status_code": statusCode, "response": body}
+	}
+
+	result := map[string]interface{}{"name": file.Name, "profile_id": existing.ProfileID}
+	if !existing.Custom {
+		result["status"] = "skipped, built-in profile"
+		return result
+	}
+
+	disabled := checksMissing(checks, existing.Checks)
+	enabled := checksMissing(existing.Checks, checks)
+	if len(disabled) == 0 && len(enabled) == 0 && existing.SortOrder == file.SortOrder && existing.Name == file.Name {
+		result["status"] = "unchanged"
+		return result
+	}
+	result["disabled"] = disabled
+	result["enabled"] = enabled
+
+	if dryRun {
+		result["status"] = "would update"
+		return result
+	}
+
+	statusCode, body := updateScanProfile(ScanProfile{ProfileID: existing.ProfileID, Name: file.Name, Custom: true, Checks: checks, SortOrder: file.SortOrder})
+	result["status_code"] = statusCode
+	if statusCode >= 300 {
+		result["status"] = "error"
+		result["response"] = body
+	} else {
+		result["status"] = "updated"
+	}
+	return result
+}
+
+// Create a scan profile
+func createScanProfile(scanProfile ScanProfile) (int, string) {
+	requestJson, err := json.Marshal(scanProfile)
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/scanning_profiles"), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return 500, fmt.Sprintf("Error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Sprintf("Error reading response body: %v", err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
+func init() {
+	ApplyCmd.Flags().String("dir", ".", "Directory to read the scan profiles from")
+	ApplyCmd.Flags().Bool("dry-run", false, "Show the changes without saving them")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// applyCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// applyCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
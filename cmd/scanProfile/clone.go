@@ -0,0 +1,65 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scanProfile
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// cloneCmd represents the clone command
+var CloneCmd = &cobra.Command{
+	Use:   "clone <id>",
+	Short: "Copy a scan profile under a new name",
+	Long: `Creates a custom scan profile with the checks of an existing profile, given by ID or name. Example:
+
+acucli scanProfile clone "Full Scan" --name "Full Scan without DoS"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.Args(resolver.KindScanProfile, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--name is required"), "Error")
+			return
+		}
+
+		profileID, err := resolver.Resolve(resolver.KindScanProfile, args[0])
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profile")
+			return
+		}
+
+		source, err := fetchScanProfile(profileID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting scan profile")
+			return
+		}
+
+		clone := ScanProfile{Name: name, Custom: true, Checks: source.Checks, SortOrder: source.SortOrder}
+		if clone.Checks == nil {
+			clone.Checks = []string{}
+		}
+
+		makeRequest(clone)
+		resolver.Invalidate(resolver.KindScanProfile)
+	},
+}
+
+func init() {
+	CloneCmd.Flags().String("name", "", "Name of the new scan profile")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// cloneCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// cloneCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
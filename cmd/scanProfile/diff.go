@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scanProfile
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// diffCmd represents the diff command
+var DiffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Compare the checks of two scan profiles",
+	Long: `Compares the checks of two scan profiles given by ID or name. The checks of a profile are the disabled checks,
+so "added" are the checks disabled in b but not in a and "removed" are the checks disabled in a but not in b. Example:
+
+acucli scanProfile diff "Full Scan" "My Slim Profile"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completion.Args(resolver.KindScanProfile, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ids, err := resolver.ResolveAll(resolver.KindScanProfile, args)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profiles")
+			return
+		}
+
+		a, err := fetchScanProfile(ids[0])
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting scan profile")
+			return
+		}
+		b, err := fetchScanProfile(ids[1])
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting scan profile")
+			return
+		}
+
+		added := checksMissing(b.Checks, a.Checks)
+		removed := checksMissing(a.Checks, b.Checks)
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"a":         map[string]interface{}{"profile_id": a.ProfileID, "name": a.Name, "checks": len(a.Checks)},
+			"b":         map[string]interface{}{"profile_id": b.ProfileID, "name": b.Name, "checks": len(b.Checks)},
+			"added":     added,
+			"removed":   removed,
+			"identical": len(added) == 0 && len(removed) == 0,
+		})
+	},
+}
+
+// Checks of a which are not in b, sorted
+func checksMissing(a, b []string) []string {
+	inB := make(map[string]bool)
+	for _, check := range b {
+		inB[check] = true
+	}
+
+	result := []string{}
+	for _, check := range a {
+		if !inB[check] {
+			result = append(result, check)
+			inB[check] = true
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func init() {
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// diffCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// diffCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
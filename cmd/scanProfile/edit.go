@@ -0,0 +1,224 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scanProfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// editCmd represents the edit command
+var EditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Enable or disable checks of a custom scan profile",
+	Long: `Enables or disables checks of a custom scan profile given by ID or name. Check paths are validated against
+the checks known by the server, a folder path (e.g. wvs/Scripts/PerFile) covers every check below it.
+Disabled checks are applied first, so a check can be enabled inside a disabled folder. Example:
+
+acucli scanProfile edit "My Slim Profile" --disable-check wvs/Scripts/PerFile --enable-check wvs/Scripts/PerFile/Backup_File.script : Disables the folder except one check
+acucli scanProfile edit "My Slim Profile" --disable-check wvs/Crawler --dry-run : Shows the changes without saving them`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.Args(resolver.KindScanProfile, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		enable, _ := cmd.Flags().GetStringArray("enable-check")
+		disable, _ := cmd.Flags().GetStringArray("disable-check")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if len(enable) == 0 && len(disable) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("use --enable-check or --disable-check"), "Error")
+			return
+		}
+
+		profileID, err := resolver.Resolve(resolver.KindScanProfile, args[0])
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profile")
+			return
+		}
+
+		profile, err := fetchScanProfile(profileID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting scan profile")
+			return
+		}
+		if !profile.Custom {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan profile %q is built-in, clone it first", profile.Name), "Error")
+			return
+		}
+
+		known, err := fetchChecks()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting checks")
+			return
+		}
+		if invalid := invalidChecks(append(append([]string{}, enable...), disable...), known); len(invalid) > 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("unknown checks: %s", strings.Join(invalid, ", ")), "Error")
+			return
+		}
+
+		checks := editChecks(profile.Checks, enable, disable, known)
+		added := checksMissing(checks, profile.Checks)
+		removed := checksMissing(profile.Checks, checks)
+
+		result := map[string]interface{}{
+			"profile_id": profile.ProfileID,
+			"name":       profile.Name,
+			"dry_run":    dryRun,
+			"disabled":   added,
+			"enabled":    removed,
+		}
+
+		if !dryRun && (len(added) > 0 || len(removed) > 0) {
+			profile.Checks = checks
+			statusCode, body := updateScanProfile(profile)
+			result["status_code"] = statusCode
+			if body != "" {
+				result["response"] = body
+			}
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(result)
+	},
+}
+
+// Apply the changes to the list of disabled checks. Disabling a check adds it, enabling a check then removes it
+// and every check below it from the list. Enabling a check inside a disabled folder replaces the folder by the
+// known checks below it, so that only the enabled check is taken out.
+func editChecks(checks []string, enable []string, disable []string, known []string) []string {
+	disabled := make(map[string]bool)
+	for _, check := range checks {
+		disabled[check] = true
+	}
+	for _, check := range disable {
+		disabled[check] = true
+	}
+
+	for _, check := range enable {
+		for existing := range disabled {
+			if existing == check || strings.HasPrefix(existing, check+"/") {
+				delete(disabled, existing)
+			} else if strings.HasPrefix(check, existing+"/") {
+				delete(disabled, existing)
+				for _, k := range known {
+					if strings.HasPrefix(k, existing+"/") && k != check && !strings.HasPrefix(k, check+"/") {
+						disabled[k] = true
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(disabled))
+	for check := range disabled {
+		result = append(result, check)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Checks that are neither a known check nor a folder of known checks
+func invalidChecks(checks []string, known []string) []string {
+	var invalid []string
+	for _, check := range checks {
+		valid := false
+		for _, k := range known {
+			if k == check || strings.HasPrefix(k, check+"/") {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			invalid = append(invalid, check)
+		}
+	}
+	return invalid
+}
+
+// Fetch the paths of the checks known by the server
+func fetchChecks() ([]string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/checks", viper.GetString("URL")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error listing checks, status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Checks []string `json:"checks"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return response.Checks, nil
+}
+
+// Save the name and checks of a custom scan profile
+func updateScanProfile(profile ScanProfile) (int, string) {
+	update := ScanProfile{Name: profile.Name, Custom: profile.Custom, Checks: profile.Checks, SortOrder: profile.SortOrder}
+	requestJson, err := json.Marshal(update)
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s%s%s", viper.GetString("URL"), "/scanning_profiles/", profile.ProfileID), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return 500, fmt.Sprintf("Error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Sprintf("Error reading response body: %v", err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
+func init() {
+	EditCmd.Flags().StringArray("enable-check", []string{}, "Check path to enable, can be repeated")
+	EditCmd.Flags().StringArray("disable-check", []string{}, "Check path to disable, can be repeated")
+	EditCmd.Flags().Bool("dry-run", false, "Show the changes without saving them")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// editCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// editCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package scanProfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"gopkg.in/yaml.v3"
+)
+
+// profileFile is the stored form of a custom scan profile. It leaves out the
+// profile ID so the same files can be applied to any server, profiles are
+// matched by name.
+type profileFile struct {
+	Name      string   `json:"name" yaml:"name"`
+	SortOrder int      `json:"sort_order" yaml:"sort_order"`
+	Checks    []string `json:"checks" yaml:"checks"`
+}
+
+// Characters which are not allowed in file names on every platform
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+func profileFileName(name string, format string) string {
+	name = strings.TrimSpace(fileNameReplacer.Replace(name))
+	if name == "" {
+		name = "default"
+	}
+	return name + "." + format
+}
+
+// Sorted copy of the checks so files only change when the profile does
+func sortedChecks(checks []string) []string {
+	sorted := append([]string{}, checks...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func marshalProfileFile(profile profileFile, format string) ([]byte, error) {
+	if format == "yaml" {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(profile); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// exportAllCmd represents the export-all command
+var ExportAllCmd = &cobra.Command{
+	Use:   "export-all",
+	Short: "Export every custom scan profile to a directory",
+	Long: `Writes every custom scan profile to <Name>.json (or .yaml) in the given directory, with sorted checks so the
+files can be kept in git. Built-in profiles are skipped. Apply the files back with "scanProfile apply". Example:
+
+acucli scanProfile export-all --dir profiles/
+acucli scanProfile export-all --dir profiles/ --format yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "yaml" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid format %q (json, yaml)", format), "Error")
+			return
+		}
+
+		profiles, err := fetchScanProfiles()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing scan profiles")
+			return
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating directory")
+			return
+		}
+
+		files := make(map[string]string)
+		written := make(map[string]string)
+		for _, profile := range profiles {
+			if !profile.Custom {
+				continue
+			}
+
+			fileName := profileFileName(profile.Name, format)
+			if other, ok := written[fileName]; ok {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan profiles %q and %q would both be written to %s", other, profile.Name, fileName), "Error")
+				return
+			}
+			written[fileName] = profile.Name
+
+			data, err := marshalProfileFile(profileFile{
+				Name:      profile.Name,
+				SortOrder: profile.SortOrder,
+				Checks:    sortedChecks(profile.Checks),
+			}, format)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error serializing scan profile")
+				return
+			}
+
+			path := filepath.Join(dir, fileName)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error writing scan profile")
+				return
+			}
+			files[profile.Name] = path
+		}
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"status": "success",
+			"dir":    dir,
+			"files":  files,
+		})
+	},
+}
+
+func init() {
+	ExportAllCmd.Flags().String("dir", ".", "Directory to write the scan profiles to")
+	ExportAllCmd.Flags().String("format", "json", "File format: json or yaml")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// exportAllCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// exportAllCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
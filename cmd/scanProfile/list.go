@@ -57,6 +57,37 @@ var ListCmd = &cobra.Command{
 	},
 }
 
+// Fetch every scan profile, built-in and custom
+func fetchScanProfiles() ([]ScanProfile, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", viper.GetString("URL"), "/scanning_profiles"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error listing scan profiles, status code: %d", resp.StatusCode)
+	}
+
+	var scanProfiles ScanProfiles
+	err = json.Unmarshal(body, &scanProfiles)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return scanProfiles.ScanningProfiles, nil
+}
+
 func init() {
 
 	// Here you will define your flags and configuration settings.
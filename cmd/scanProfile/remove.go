@@ -10,9 +10,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 // removeCmd represents the remove command
@@ -23,9 +23,14 @@ var RemoveCmd = &cobra.Command{
 
 cat scanProfileids.txt | acucli scanProfile remove`,
 	Run: func(cmd *cobra.Command, args []string) {
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindScanProfile)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profiles")
+			return
+		}
 		if input != nil && len(input) > 0 {
 			makeDeleteRequest(input)
+			resolver.Invalidate(resolver.KindScanProfile)
 		} else {
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no scan profile IDs provided"), "Error")
 		}
@@ -14,15 +14,17 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type ScanProfile struct {
 	Checks    []string `json:"checks"`
 	Custom    bool     `json:"custom"`
 	Name      string   `json:"name"`
-	ProfileID string   `json:"profile_id"`
+	ProfileID string   `json:"profile_id,omitempty"`
 	SortOrder int      `json:"sort_order"`
 }
 
@@ -60,6 +62,12 @@ var ScanProfileCmd = &cobra.Command{
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan profile ID is required"), "Error")
 			return
 		}
+		profileID, err := resolver.Resolve(resolver.KindScanProfile, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profile")
+			return
+		}
+		id = profileID
 
 		if cmd.Flags().Changed("export") {
 			output, _ := cmd.Flags().GetString("output")
@@ -147,6 +155,38 @@ func exportScanProfile(id string, path string) {
 	})
 }
 
+// Fetch a scan profile
+func fetchScanProfile(id string) (ScanProfile, error) {
+	var scanProfile ScanProfile
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s%s", viper.GetString("URL"), "/scanning_profiles/", id), nil)
+	if err != nil {
+		return scanProfile, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return scanProfile, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return scanProfile, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return scanProfile, fmt.Errorf("scan profile %s not found, status code: %d", id, resp.StatusCode)
+	}
+
+	err = json.Unmarshal(body, &scanProfile)
+	if err != nil {
+		return scanProfile, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return scanProfile, nil
+}
+
 func GetScanProfileRequest(id string) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s%s", viper.GetString("URL"), "/scanning_profiles/", id), nil)
 	if err != nil {
@@ -181,7 +221,8 @@ func GetScanProfileRequest(id string) {
 }
 
 func init() {
-	ScanProfileCmd.Flags().StringVarP(&id, "id", "", "", "Scan Profile ID")
+	ScanProfileCmd.Flags().StringVarP(&id, "id", "", "", "Scan Profile ID or name")
+	completion.Register(ScanProfileCmd, resolver.KindScanProfile, "id")
 	ScanProfileCmd.MarkFlagRequired("id")
 	ScanProfileCmd.Flags().BoolP("export", "e", false, "Enable export")
 	ScanProfileCmd.Flags().StringP("output", "o", ".", "Output directory")
@@ -191,6 +232,11 @@ func init() {
 
 	ScanProfileCmd.AddCommand(ListCmd)
 	ScanProfileCmd.AddCommand(AddCmd)
+	ScanProfileCmd.AddCommand(DiffCmd)
+	ScanProfileCmd.AddCommand(CloneCmd)
+	ScanProfileCmd.AddCommand(EditCmd)
+	ScanProfileCmd.AddCommand(ExportAllCmd)
+	ScanProfileCmd.AddCommand(ApplyCmd)
 	ScanProfileCmd.AddCommand(RemoveCmd)
 
 	// Here you will define your flags and configuration settings.
@@ -0,0 +1,162 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package serve
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tosbaa/acucli/helpers/notify"
+	"github.com/tosbaa/acucli/helpers/vulnformat"
+)
+
+// Formats a job can be written in
+var jobFormats = []string{"html", "csv", vulnformat.FormatDefectDojo, vulnformat.FormatGenericFindings}
+
+// Default wait timeout of a job in seconds, the same as auto mode
+const defaultJobTimeout = 800
+
+// artifact is an entry of GET /jobs/{id}/artifacts
+type artifact struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	URL  string `json:"url"`
+}
+
+// The REST API of acucli serve, every request needs the API token as a bearer token
+func newHandler(q *jobQueue, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /jobs", func(w http.ResponseWriter, r *http.Request) {
+		var request jobRequest
+		decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid job: %v", err))
+			return
+		}
+		if err := validateJob(&request); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		j, err := q.add(request)
+		if err == errQueueFull {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Location", "/jobs/"+j.ID)
+		writeJSON(w, http.StatusAccepted, j)
+	})
+
+	mux.HandleFunc("GET /jobs", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": q.list()})
+	})
+
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		j, ok := q.get(r.PathValue("id"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, j)
+	})
+
+	mux.HandleFunc("GET /jobs/{id}/artifacts", func(w http.ResponseWriter, r *http.Request) {
+		j, ok := q.get(r.PathValue("id"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		artifacts := []artifact{}
+		for _, name := range j.Artifacts {
+			path, _ := q.artifactPath(j.ID, name)
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			artifacts = append(artifacts, artifact{Name: name, Size: info.Size(), URL: "/jobs/" + j.ID + "/artifacts/" + name})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": j.ID, "status": j.Status, "artifacts": artifacts})
+	})
+
+	mux.HandleFunc("GET /jobs/{id}/artifacts/{name}", func(w http.ResponseWriter, r *http.Request) {
+		path, ok := q.artifactPath(r.PathValue("id"), r.PathValue("name"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "artifact not found")
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", r.PathValue("name")))
+		http.ServeFile(w, r, path)
+	})
+
+	return authenticate(token, mux)
+}
+
+// Reject requests without the API token
+func authenticate(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="acucli"`)
+			writeError(w, http.StatusUnauthorized, "invalid or missing API token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Check a job request and fill in the defaults
+func validateJob(request *jobRequest) error {
+	request.URL = strings.TrimSpace(request.URL)
+	if request.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	request.Format = strings.ToLower(request.Format)
+	if request.Format == "" {
+		request.Format = "html"
+	}
+	valid := false
+	for _, format := range jobFormats {
+		if request.Format == format {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid format %q (%s)", request.Format, strings.Join(jobFormats, ", "))
+	}
+
+	if _, err := notify.ParseGate(request.FailOn); err != nil {
+		return fmt.Errorf("invalid fail_on: %v", err)
+	}
+	if request.Timeout < 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if request.Timeout == 0 {
+		request.Timeout = defaultJobTimeout
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
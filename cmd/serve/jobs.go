@@ -0,0 +1,267 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package serve
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tosbaa/acucli/cmd/auto"
+)
+
+// Job states
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobCompleted = "completed"
+	jobFailed    = "failed"
+)
+
+// Name of the state file in the directory of a job, the artifacts are written next to it
+const jobFile = "job.json"
+
+// jobRequest is the body of POST /jobs
+type jobRequest struct {
+	URL      string `json:"url"`
+	Profile  string `json:"profile,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Template string `json:"template,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"`
+	FailOn   string `json:"fail_on,omitempty"`
+}
+
+// job is an auto run requested through the API
+type job struct {
+	ID         string                   `json:"id"`
+	Status     string                   `json:"status"`
+	Request    jobRequest               `json:"request"`
+	CreatedAt  time.Time                `json:"created_at"`
+	StartedAt  *time.Time               `json:"started_at,omitempty"`
+	FinishedAt *time.Time               `json:"finished_at,omitempty"`
+	Result     *auto.Result             `json:"result,omitempty"`
+	Artifacts  []string                 `json:"artifacts"`
+	Steps      []map[string]interface{} `json:"steps"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// errQueueFull is returned when no more jobs can be queued
+var errQueueFull = fmt.Errorf("the job queue is full")
+
+// jobQueue holds the jobs in memory and in a directory per job, queued jobs are run by the workers
+type jobQueue struct {
+	dir     string
+	mu      sync.Mutex
+	jobs    map[string]*job
+	pending chan string
+}
+
+// Load the jobs of dir. Queued jobs are queued again, jobs which were running when the server
+// stopped are failed as their target and scan were not cleaned up.
+func newJobQueue(dir string, size int) (*jobQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating job directory: %v", err)
+	}
+
+	jobs := make(map[string]*job)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job directory: %v", err)
+	}
+	var queued []*job
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), jobFile))
+		if err != nil {
+			continue
+		}
+		var j job
+		if err := json.Unmarshal(data, &j); err != nil || j.ID != entry.Name() {
+			continue
+		}
+		jobs[j.ID] = &j
+		if j.Status == jobQueued {
+			queued = append(queued, &j)
+		}
+	}
+	sort.Slice(queued, func(i, k int) bool { return queued[i].CreatedAt.Before(queued[k].CreatedAt) })
+
+	if size < len(queued) {
+		size = len(queued)
+	}
+	q := &jobQueue{dir: dir, jobs: jobs, pending: make(chan string, size)}
+	for _, j := range jobs {
+		if j.Status == jobRunning {
+			q.update(j.ID, func(j *job) {
+				j.Status = jobFailed
+				j.Error = "the server stopped while the job was running"
+				now := time.Now()
+				j.FinishedAt = &now
+			})
+		}
+	}
+	for _, j := range queued {
+		q.pending <- j.ID
+	}
+	return q, nil
+}
+
+// Directory of a job
+func (q *jobQueue) jobDir(id string) string {
+	return filepath.Join(q.dir, id)
+}
+
+// Add a job to the queue
+func (q *jobQueue) add(request jobRequest) (job, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return job{}, fmt.Errorf("error creating job ID: %v", err)
+	}
+	j := &job{
+		ID:        hex.EncodeToString(buf),
+		Status:    jobQueued,
+		Request:   request,
+		CreatedAt: time.Now(),
+		Artifacts: []string{},
+		Steps:     []map[string]interface{}{},
+	}
+	if err := os.MkdirAll(q.jobDir(j.ID), 0700); err != nil {
+		return job{}, fmt.Errorf("error creating job directory: %v", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.save(j); err != nil {
+		os.RemoveAll(q.jobDir(j.ID))
+		return job{}, err
+	}
+	select {
+	case q.pending <- j.ID:
+	default:
+		os.RemoveAll(q.jobDir(j.ID))
+		return job{}, errQueueFull
+	}
+	q.jobs[j.ID] = j
+	return *j, nil
+}
+
+// Get a copy of a job
+func (q *jobQueue) get(id string) (job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return job{}, false
+	}
+	return *j, true
+}
+
+// List copies of all jobs, oldest first
+func (q *jobQueue) list() []job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		jobs = append(jobs, *j)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].CreatedAt.Before(jobs[k].CreatedAt) })
+	return jobs
+}
+
+// Change a job and persist it
+func (q *jobQueue) update(id string, change func(j *job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return
+	}
+	change(j)
+	if err := q.save(j); err != nil {
+		fmt.Fprintf(os.Stderr, "error saving job %s: %v\n", id, err)
+	}
+}
+
+// Write the state file of a job, the caller holds the lock
+func (q *jobQueue) save(j *job) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding job: %v", err)
+	}
+	path := filepath.Join(q.jobDir(j.ID), jobFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing job: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Run queued jobs until the queue is closed
+func (q *jobQueue) work() {
+	for id := range q.pending {
+		q.run(id)
+	}
+}
+
+// Run the auto workflow of a job with the job directory as output path
+func (q *jobQueue) run(id string) {
+	var request jobRequest
+	q.update(id, func(j *job) {
+		request = j.Request
+		j.Status = jobRunning
+		now := time.Now()
+		j.StartedAt = &now
+	})
+
+	result, err := auto.Run(auto.Options{
+		TargetURL:        request.URL,
+		ScanProfileID:    request.Profile,
+		ReportTemplateID: request.Template,
+		OutputFormat:     request.Format,
+		OutputPath:       q.jobDir(id),
+		WaitTimeout:      request.Timeout,
+		FailOn:           request.FailOn,
+		Progress: func(step map[string]interface{}) {
+			q.update(id, func(j *job) { j.Steps = append(j.Steps, step) })
+		},
+	})
+
+	q.update(id, func(j *job) {
+		now := time.Now()
+		j.FinishedAt = &now
+		if err != nil {
+			j.Status = jobFailed
+			j.Error = err.Error()
+		} else {
+			j.Status = jobCompleted
+		}
+		// Only the names of the artifacts are exposed, not where the server keeps them
+		for i, file := range result.Files {
+			result.Files[i] = filepath.Base(file)
+		}
+		j.Artifacts = append([]string{}, result.Files...)
+		if result.ScanID != "" {
+			j.Result = &result
+		}
+	})
+}
+
+// Path of an artifact of a job, only the files the job wrote can be downloaded
+func (q *jobQueue) artifactPath(id string, name string) (string, bool) {
+	j, ok := q.get(id)
+	if !ok {
+		return "", false
+	}
+	for _, artifact := range j.Artifacts {
+		if artifact == name {
+			return filepath.Join(q.jobDir(id), artifact), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package serve
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+)
+
+// serveCmd represents the serve command
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the auto workflow as a REST service",
+	Long: `Serves a small REST API which runs the auto workflow (add target, scan, report, clean up) as jobs. Jobs are
+queued and at most --concurrency of them run at the same time. The state and artifacts of every job are kept in
+--dir, so jobs survive a restart. Every request needs the API token (serve.token in the config file or --token)
+as a bearer token.
+
+POST /jobs                          {"url": "https://example.com", "profile": "Full Scan", "format": "html"}
+GET  /jobs                          list the jobs
+GET  /jobs/{id}                     status, steps and result of a job
+GET  /jobs/{id}/artifacts           list the files written by a job
+GET  /jobs/{id}/artifacts/{name}    download a file
+
+Example:
+
+acucli serve --listen :8080 --concurrency 2
+curl -H "Authorization: Bearer $TOKEN" -d '{"url": "https://example.com", "format": "defectdojo"}' localhost:8080/jobs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		token, _ := cmd.Flags().GetString("token")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		queueSize, _ := cmd.Flags().GetInt("queue-size")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		if token == "" {
+			token = viper.GetString("serve.token")
+		}
+		if token == "" {
+			return fmt.Errorf("an API token is required, set serve.token in the config file or use --token")
+		}
+		if dir == "" {
+			dir = viper.GetString("serve.dir")
+		}
+		if dir == "" {
+			dir = defaultJobDir()
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+
+		queue, err := newJobQueue(dir, queueSize)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < concurrency; i++ {
+			go queue.work()
+		}
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           newHandler(queue, token),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			server.Shutdown(shutdown)
+		}()
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"status":      "listening",
+			"listen":      listen,
+			"dir":         dir,
+			"concurrency": concurrency,
+		})
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("error serving: %v", err)
+		}
+		return nil
+	},
+}
+
+// Default directory of the jobs, next to the local database
+func defaultJobDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "acucli", "jobs")
+}
+
+func init() {
+	ServeCmd.Flags().String("listen", ":8080", "Address to listen on")
+	ServeCmd.Flags().String("token", "", "API token clients have to send (default is serve.token from the config file)")
+	ServeCmd.Flags().Int("concurrency", 2, "Number of jobs running at the same time")
+	ServeCmd.Flags().Int("queue-size", 100, "Number of jobs which can wait in the queue")
+	ServeCmd.Flags().String("dir", "", "Directory of the job state and artifacts (default is serve.dir from the config file or jobs in the user config directory)")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// serveCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// serveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -12,9 +12,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type Target struct {
@@ -40,20 +42,33 @@ var AddCmd = &cobra.Command{
 
 	 echo "https://127.0.0.1" | acucli target add : Add the target without target group
 	 cat targets.txt | acucli target add --gid=cd3db1f4-6275-478c-8830-8d96d37120f3 : Add targets from a file with target group
+	 echo "https://127.0.0.1" | acucli target add --criticality=normal : Add the target with normal criticality
 	 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		groups := []string{}
 		input := filehelper.ReadStdin()
 		inputGID, _ := cmd.Flags().GetString("gid")
 		if inputGID != "" {
-			groups = append(groups, inputGID)
+			groupID, err := resolver.Resolve(resolver.KindGroup, inputGID)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+				return
+			}
+			groups = append(groups, groupID)
+		}
+
+		criticalityFlag, _ := cmd.Flags().GetString("criticality")
+		criticality, err := parseCriticality(criticalityFlag)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
 		}
 
 		if input != nil {
 			targets := []Target{}
 
 			for _, line := range input {
-				targets = append(targets, Target{Address: line, Description: "", Type: "default", Criticality: 30})
+				targets = append(targets, Target{Address: line, Description: "", Type: "default", Criticality: criticality})
 			}
 			makeRequest(targets, groups)
 		} else {
@@ -89,13 +104,18 @@ func makeRequest(t []Target, groups []string) {
 		jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
 		return
 	}
+	if resp.StatusCode < 300 {
+		resolver.Invalidate(resolver.KindTarget)
+	}
 
 	// Output only the JSON response
 	jsonoutput.OutputRawJSON(responseBody)
 }
 
 func init() {
-	AddCmd.Flags().StringVarP(&gid, "gid", "g", "", "Group ID (To assign the targets to the group)")
+	AddCmd.Flags().StringVarP(&gid, "gid", "g", "", "Group ID or name (To assign the targets to the group)")
+	completion.Register(AddCmd, resolver.KindGroup, "gid")
+	AddCmd.Flags().String("criticality", defaultCriticality, "Criticality of the targets: critical, high, normal or low")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,488 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"gopkg.in/yaml.v3"
+)
+
+type targetManifest struct {
+	Targets []manifestTarget `yaml:"targets"`
+}
+
+type manifestTarget struct {
+	Address       string               `yaml:"address"`
+	Description   *string              `yaml:"description"`
+	Criticality   *criticalityLevel    `yaml:"criticality"`
+	Groups        []string             `yaml:"groups"`
+	Configuration *targetConfiguration `yaml:"configuration"`
+}
+
+// planAction is a single step of the plan computed by apply
+type planAction struct {
+	Action   string                  `json:"action"`
+	Address  string                  `json:"address"`
+	TargetID string                  `json:"target_id,omitempty"`
+	Changes  map[string]configChange `json:"changes,omitempty"`
+	Status   string                  `json:"status,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+
+	desired      manifestTarget
+	configPatch  map[string]interface{}
+	groupsAdd    []string
+	groupsRemove []string
+}
+
+// ApplyCmd represents the apply command
+var ApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Sync targets with a manifest file",
+	Long: `Reads a YAML manifest of targets, computes the changes needed to bring the server in line with it and applies them.
+Targets are matched by address. Groups are given by name or ID and must exist. Only the configuration fields
+that are declared are managed. Targets which are not in the manifest are only deleted with --prune.
+New targets without a criticality are created as critical, like with target add and import.
+Passwords are masked in the output. As the server never returns them, a declared password cannot be compared,
+so its field is sent on every apply and listed as a change with "secret": true. Example:
+
+acucli target apply -f targets.yaml --dry-run : Prints the plan without changing anything
+acucli target apply -f targets.yaml : Applies the plan
+acucli target apply -f targets.yaml --prune : Also deletes targets which are not in the manifest
+
+Manifest example:
+
+targets:
+  - address: https://app.example.com
+    description: Main application
+    criticality: high
+    groups: [production]
+    configuration:
+      scan_speed: slow
+      excluded_hours_id: Office hours
+      excluded_paths: [/logout, /admin/reset]
+      custom_headers: ["X-Scanner: acunetix"]
+      login:
+        kind: none`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--file is required"), "Error")
+			return
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		manifest, err := readTargetManifest(file)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading manifest")
+			return
+		}
+
+		actions, unchanged, err := planTargetManifest(manifest, prune)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error computing plan")
+			return
+		}
+
+		if !dryRun {
+			applyTargetPlan(actions)
+		}
+
+		summary := map[string]int{"create": 0, "update": 0, "delete": 0, "unchanged": unchanged}
+		for _, action := range actions {
+			summary[action.Action]++
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"dry_run": dryRun,
+			"summary": summary,
+			"actions": actions,
+		})
+	},
+}
+
+func readTargetManifest(path string) (targetManifest, error) {
+	var manifest targetManifest
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range manifest.Targets {
+		if t.Address == "" {
+			return manifest, fmt.Errorf("target #%d has no address", i+1)
+		}
+		key := normalizeAddress(t.Address)
+		if seen[key] {
+			return manifest, fmt.Errorf("target %s is declared more than once", t.Address)
+		}
+		seen[key] = true
+	}
+
+	return manifest, nil
+}
+
+// Normalize an address so that trivial differences do not cause a mismatch
+func normalizeAddress(address string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(address)), "/")
+}
+
+// Compute the actions needed to apply the manifest and the number of unchanged targets
+func planTargetManifest(manifest targetManifest, prune bool) ([]*planAction, int, error) {
+	existing, err := fetchAllTargets("")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groups, err := scandata.FetchTargetGroups()
+	if err != nil {
+		return nil, 0, err
+	}
+	groupIDs := make(map[string]string)
+	groupNames := make(map[string]string)
+	for _, g := range groups {
+		groupIDs[strings.ToLower(g.Name)] = g.GroupID
+		groupIDs[g.GroupID] = g.GroupID
+		groupNames[g.GroupID] = g.Name
+	}
+
+	// Resolve the declared groups and collect the current memberships of those groups
+	memberships := make(map[string][]string)
+	for _, t := range manifest.Targets {
+		for _, group := range t.Groups {
+			groupID, ok := groupIDs[strings.ToLower(group)]
+			if !ok {
+				return nil, 0, fmt.Errorf("target group %q of %s does not exist", group, t.Address)
+			}
+			if _, fetched := memberships[groupID]; fetched {
+				continue
+			}
+			members, err := FetchGroupTargetIDs(groupID)
+			if err != nil {
+				return nil, 0, err
+			}
+			memberships[groupID] = members
+		}
+	}
+	// Groups only matter for targets that declare them, fetch the rest lazily per target
+	targetGroupsOf := func(targetID string) ([]string, error) {
+		var result []string
+		for _, g := range groups {
+			members, fetched := memberships[g.GroupID]
+			if !fetched {
+				members, err = FetchGroupTargetIDs(g.GroupID)
+				if err != nil {
+					return nil, err
+				}
+				memberships[g.GroupID] = members
+			}
+			for _, member := range members {
+				if member == targetID {
+					result = append(result, g.GroupID)
+					break
+				}
+			}
+		}
+		return result, nil
+	}
+
+	existingByAddress := make(map[string]TargetListItem)
+	for _, t := range existing {
+		existingByAddress[normalizeAddress(t.Address)] = t
+	}
+
+	var actions []*planAction
+	unchanged := 0
+	declared := make(map[string]bool)
+	resolved := make(map[string]string)
+
+	for _, desired := range manifest.Targets {
+		key := normalizeAddress(desired.Address)
+		declared[key] = true
+
+		desiredGroups := []string{}
+		for _, group := range desired.Groups {
+			desiredGroups = append(desiredGroups, groupIDs[strings.ToLower(group)])
+		}
+
+		desiredConfig, err := configurationMap(desired.Configuration)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := resolveConfigReferences(desiredConfig, resolved); err != nil {
+			return nil, 0, err
+		}
+
+		current, ok := existingByAddress[key]
+		if !ok {
+			action := &planAction{Action: "create", Address: desired.Address, Changes: map[string]configChange{}, desired: desired}
+			if desired.Description != nil {
+				action.Changes["description"] = configChange{To: *desired.Description}
+			}
+			if desired.Criticality != nil {
+				action.Changes["criticality"] = configChange{To: int(*desired.Criticality)}
+			}
+			if len(desiredGroups) > 0 {
+				action.Changes["groups"] = configChange{To: groupNameList(desiredGroups, groupNames)}
+			}
+			for field, value := range desiredConfig {
+				action.Changes["configuration."+field] = configChange{To: maskSecrets(field, value), Secret: declaresSecret(field, value)}
+			}
+			action.configPatch = desiredConfig
+			action.groupsAdd = desiredGroups
+			actions = append(actions, action)
+			continue
+		}
+
+		action := &planAction{Action: "update", Address: current.Address, TargetID: current.TargetID, Changes: map[string]configChange{}, desired: desired}
+		if desired.Description != nil && *desired.Description != current.Description {
+			action.Changes["description"] = configChange{From: current.Description, To: *desired.Description}
+		}
+		if desired.Criticality != nil && int(*desired.Criticality) != current.Criticality {
+			action.Changes["criticality"] = configChange{From: current.Criticality, To: int(*desired.Criticality)}
+		}
+
+		if desired.Groups != nil {
+			currentGroups, err := targetGroupsOf(current.TargetID)
+			if err != nil {
+				return nil, 0, err
+			}
+			action.groupsAdd = stringsMissing(desiredGroups, currentGroups)
+			action.groupsRemove = stringsMissing(currentGroups, desiredGroups)
+			if len(action.groupsAdd) > 0 || len(action.groupsRemove) > 0 {
+				action.Changes["groups"] = configChange{From: groupNameList(currentGroups, groupNames), To: groupNameList(desiredGroups, groupNames)}
+			}
+		}
+
+		if len(desiredConfig) > 0 {
+			currentConfig, err := fetchTargetConfiguration(current.TargetID)
+			if err != nil {
+				return nil, 0, err
+			}
+			configChanges := diffConfiguration(desiredConfig, currentConfig)
+			changedConfig := make(map[string]interface{})
+			for field, change := range configChanges {
+				action.Changes["configuration."+field] = change
+				changedConfig[field] = desiredConfig[field]
+			}
+			if len(changedConfig) > 0 {
+				action.configPatch = mergeConfiguration(changedConfig, currentConfig)
+			}
+		}
+
+		if len(action.Changes) == 0 {
+			unchanged++
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	if prune {
+		for _, t := range existing {
+			if !declared[normalizeAddress(t.Address)] {
+				actions = append(actions, &planAction{Action: "delete", Address: t.Address, TargetID: t.TargetID})
+			}
+		}
+	}
+
+	return actions, unchanged, nil
+}
+
+// Execute the plan, recording the outcome on each action
+func applyTargetPlan(actions []*planAction) {
+	var deleteIDs []string
+
+	for _, action := range actions {
+		var err error
+		switch action.Action {
+		case "create":
+			err = applyCreate(action)
+		case "update":
+			err = applyUpdate(action)
+		case "delete":
+			deleteIDs = append(deleteIDs, action.TargetID)
+			continue
+		}
+
+		if err != nil {
+			action.Status = "error"
+			action.Error = err.Error()
+		} else {
+			action.Status = "applied"
+		}
+	}
+
+	if len(deleteIDs) > 0 {
+		err := deleteTargets(deleteIDs)
+		for _, action := range actions {
+			if action.Action != "delete" {
+				continue
+			}
+			if err != nil {
+				action.Status = "error"
+				action.Error = err.Error()
+			} else {
+				action.Status = "applied"
+			}
+		}
+	}
+}
+
+func applyCreate(action *planAction) error {
+	desired := action.desired
+	t := Target{Address: desired.Address, Type: "default", Criticality: criticalityLevels[defaultCriticality]}
+	if desired.Description != nil {
+		t.Description = *desired.Description
+	}
+	if desired.Criticality != nil {
+		t.Criticality = int(*desired.Criticality)
+	}
+
+	added, err := addTargets([]Target{t}, action.groupsAdd)
+	if err != nil {
+		return err
+	}
+	if len(added.Targets) == 0 {
+		return fmt.Errorf("no target ID in response")
+	}
+	action.TargetID = added.Targets[0].TargetID
+
+	if len(action.configPatch) > 0 {
+		statusCode, body := patchTargetConfiguration(action.TargetID, action.configPatch)
+		if statusCode >= 300 {
+			return fmt.Errorf("error setting configuration, status code: %d, response: %s", statusCode, body)
+		}
+	}
+
+	return nil
+}
+
+func applyUpdate(action *planAction) error {
+	body := make(map[string]interface{})
+	if change, ok := action.Changes["description"]; ok {
+		body["description"] = change.To
+	}
+	if change, ok := action.Changes["criticality"]; ok {
+		body["criticality"] = change.To
+	}
+	if len(body) > 0 {
+		if err := patchTarget(action.TargetID, body); err != nil {
+			return err
+		}
+	}
+
+	for _, groupID := range action.groupsAdd {
+		if err := patchGroupTargets(groupID, []string{action.TargetID}, nil); err != nil {
+			return err
+		}
+	}
+	for _, groupID := range action.groupsRemove {
+		if err := patchGroupTargets(groupID, nil, []string{action.TargetID}); err != nil {
+			return err
+		}
+	}
+
+	if len(action.configPatch) > 0 {
+		statusCode, responseBody := patchTargetConfiguration(action.TargetID, action.configPatch)
+		if statusCode >= 300 {
+			return fmt.Errorf("error setting configuration, status code: %d, response: %s", statusCode, responseBody)
+		}
+	}
+
+	return nil
+}
+
+// Update the fields of a target (description, criticality, type)
+func patchTarget(id string, body map[string]interface{}) error {
+	requestJson, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/targets/%s", viper.GetString("URL"), id), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error updating target, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Remove targets
+func deleteTargets(ids []string) error {
+	statusCode, _, err := DeleteTargets(ids)
+	if err != nil {
+		return err
+	}
+	if statusCode >= 300 {
+		return fmt.Errorf("error removing targets, status code: %d", statusCode)
+	}
+
+	return nil
+}
+
+// Return the elements of a that are not in b
+func stringsMissing(a, b []string) []string {
+	present := make(map[string]bool)
+	for _, s := range b {
+		present[s] = true
+	}
+	var missing []string
+	for _, s := range a {
+		if !present[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+func groupNameList(groupIDs []string, names map[string]string) []string {
+	result := []string{}
+	for _, groupID := range groupIDs {
+		if name, ok := names[groupID]; ok {
+			result = append(result, name)
+		} else {
+			result = append(result, groupID)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func init() {
+	ApplyCmd.Flags().StringP("file", "f", "", "Target manifest file (YAML)")
+	ApplyCmd.Flags().Bool("dry-run", false, "Print the plan without applying it")
+	ApplyCmd.Flags().Bool("prune", false, "Delete targets which are not declared in the manifest")
+}
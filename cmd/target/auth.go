@@ -0,0 +1,365 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+const maskedSecret = "********"
+
+// Headers whose values are treated as secrets by auth show
+var secretHeaders = []string{"authorization", "cookie", "token", "key", "secret"}
+
+// AuthCmd represents the auth command
+var AuthCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage authenticated scanning of targets",
+	Long:  `Configure HTTP authentication, form login, login sequences and session cookies/headers of targets.`,
+}
+
+// AuthSetCmd represents the auth set command
+var AuthSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the authentication of targets",
+	Long: `Sets the authentication of the targets taken from stdin. Only the given settings are changed. Example:
+
+echo "<TARGET-ID>" | acucli target auth set --http-user=admin --http-password=secret : HTTP basic/digest authentication
+echo "<TARGET-ID>" | acucli target auth set --http-user=jdoe --http-password=secret --http-domain=CORP : NTLM authentication
+echo "<TARGET-ID>" | acucli target auth set --login-url=https://app/login --login-user=jdoe --login-password=secret : Automatic form login
+echo "<TARGET-ID>" | acucli target auth set --login-sequence=login.lsr : Recorded login sequence
+echo "<TARGET-ID>" | acucli target auth set --cookie "session=abc123" --header "Authorization: Bearer eyJ..." : Pre-seeded session
+echo "<TARGET-ID>" | acucli target auth set --disable-login --disable-http-auth`,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		if input == nil || len(input) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+			return
+		}
+
+		httpUser, _ := cmd.Flags().GetString("http-user")
+		httpPassword, _ := cmd.Flags().GetString("http-password")
+		httpDomain, _ := cmd.Flags().GetString("http-domain")
+		disableHTTPAuth, _ := cmd.Flags().GetBool("disable-http-auth")
+		loginURL, _ := cmd.Flags().GetString("login-url")
+		loginUser, _ := cmd.Flags().GetString("login-user")
+		loginPassword, _ := cmd.Flags().GetString("login-password")
+		loginSequence, _ := cmd.Flags().GetString("login-sequence")
+		disableLogin, _ := cmd.Flags().GetBool("disable-login")
+		cookies, _ := cmd.Flags().GetStringArray("cookie")
+		cookieURL, _ := cmd.Flags().GetString("cookie-url")
+		headers, _ := cmd.Flags().GetStringArray("header")
+
+		overrides := make(map[string]interface{})
+
+		if disableHTTPAuth {
+			overrides["authentication"] = map[string]interface{}{"enabled": false}
+		} else if httpUser != "" {
+			username := httpUser
+			if httpDomain != "" {
+				username = fmt.Sprintf("%s\\%s", httpDomain, httpUser)
+			}
+			overrides["authentication"] = map[string]interface{}{
+				"enabled":  true,
+				"username": username,
+				"password": httpPassword,
+			}
+		}
+
+		modes := 0
+		for _, set := range []bool{disableLogin, loginUser != "", loginSequence != ""} {
+			if set {
+				modes++
+			}
+		}
+		if modes > 1 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--disable-login, --login-user and --login-sequence are mutually exclusive"), "Error")
+			return
+		}
+
+		if disableLogin {
+			overrides["login"] = map[string]interface{}{"kind": "none"}
+		} else if loginUser != "" {
+			if loginURL == "" {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("--login-url is required for form login"), "Error")
+				return
+			}
+			overrides["login"] = map[string]interface{}{
+				"kind": "automatic",
+				"credentials": map[string]interface{}{
+					"enabled":  true,
+					"username": loginUser,
+					"password": loginPassword,
+					"url":      loginURL,
+				},
+			}
+		} else if loginSequence != "" {
+			overrides["login"] = map[string]interface{}{"kind": "sequence"}
+		}
+
+		for _, header := range headers {
+			if name, _, found := strings.Cut(header, ":"); !found || strings.TrimSpace(name) == "" {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid header %q, expected \"Name: value\"", header), "Error")
+				return
+			}
+		}
+		for _, cookie := range cookies {
+			if name, _, found := strings.Cut(cookie, "="); !found || strings.TrimSpace(name) == "" {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid cookie %q, expected name=value", cookie), "Error")
+				return
+			}
+		}
+
+		if len(overrides) == 0 && len(headers) == 0 && len(cookies) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no authentication settings given"), "Error")
+			return
+		}
+
+		results := make(map[string]interface{})
+		for _, id := range input {
+			result, err := setTargetAuth(id, overrides, loginSequence, headers, cookies, cookieURL)
+			if err != nil {
+				results[id] = map[string]interface{}{
+					"status": "error",
+					"error":  err.Error(),
+				}
+				continue
+			}
+			results[id] = result
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+// AuthShowCmd represents the auth show command
+var AuthShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the authentication of targets",
+	Long: `Shows the authentication settings of the targets taken from stdin, secrets are masked. Example:
+
+echo "<TARGET-ID>" | acucli target auth show`,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		if input == nil || len(input) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+			return
+		}
+
+		results := make(map[string]interface{})
+		for _, id := range input {
+			config, err := fetchTargetConfiguration(id)
+			if err != nil {
+				results[id] = map[string]interface{}{
+					"status": "error",
+					"error":  err.Error(),
+				}
+				continue
+			}
+
+			auth := make(map[string]interface{})
+			for _, key := range []string{"login", "authentication", "custom_headers", "custom_cookies", "client_certificate_password"} {
+				if value, ok := config[key]; ok {
+					auth[key] = value
+				}
+			}
+			results[id] = maskSecrets("", auth)
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+// Apply the authentication settings to a single target
+func setTargetAuth(id string, overrides map[string]interface{}, loginSequence string, headers []string, cookies []string, cookieURL string) (map[string]interface{}, error) {
+	current, err := fetchTargetConfiguration(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+
+	// The login sequence has to be uploaded before the login kind can be switched to it
+	if loginSequence != "" {
+		uploadID, err := uploadTargetFile(id, "login_sequence", loginSequence)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading login sequence: %v", err)
+		}
+		result["login_sequence_upload_id"] = uploadID
+	}
+
+	patch := mergeConfiguration(overrides, current)
+
+	if len(headers) > 0 {
+		patch["custom_headers"] = mergeHeaders(configStrings(current["custom_headers"]), headers)
+	}
+
+	if len(cookies) > 0 {
+		if cookieURL == "" {
+			target, err := fetchTarget(id)
+			if err != nil {
+				return nil, err
+			}
+			cookieURL = target.Address
+		}
+		patch["custom_cookies"] = mergeCookies(current["custom_cookies"], cookies, cookieURL)
+	}
+
+	statusCode, responseBody := patchTargetConfiguration(id, patch)
+	if statusCode >= 300 {
+		return nil, fmt.Errorf("error setting authentication, status code: %d, response: %s", statusCode, responseBody)
+	}
+
+	result["status"] = "updated"
+	result["status_code"] = statusCode
+	return result, nil
+}
+
+// Convert a JSON list to a list of strings
+func configStrings(value interface{}) []string {
+	var result []string
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Add headers, replacing existing headers with the same name
+func mergeHeaders(current []string, headers []string) []string {
+	merged := []string{}
+	replaced := make(map[string]bool)
+	for _, header := range headers {
+		name, _, _ := strings.Cut(header, ":")
+		replaced[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	for _, header := range current {
+		name, _, _ := strings.Cut(header, ":")
+		if !replaced[strings.ToLower(strings.TrimSpace(name))] {
+			merged = append(merged, header)
+		}
+	}
+	return append(merged, headers...)
+}
+
+// Add cookies for the URL, replacing existing cookies with the same URL and name
+func mergeCookies(current interface{}, cookies []string, cookieURL string) []customCookie {
+	merged := []customCookie{}
+	replaced := make(map[string]bool)
+	for _, cookie := range cookies {
+		name, _, _ := strings.Cut(cookie, "=")
+		replaced[strings.TrimSpace(name)] = true
+	}
+
+	list, _ := current.([]interface{})
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		existing := customCookie{}
+		existing.URL, _ = entry["url"].(string)
+		existing.Cookie, _ = entry["cookie"].(string)
+		name, _, _ := strings.Cut(existing.Cookie, "=")
+		if existing.URL == cookieURL && replaced[strings.TrimSpace(name)] {
+			continue
+		}
+		merged = append(merged, existing)
+	}
+
+	for _, cookie := range cookies {
+		merged = append(merged, customCookie{URL: cookieURL, Cookie: cookie})
+	}
+	return merged
+}
+
+// Mask passwords, secret headers and cookie values
+func maskSecrets(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{})
+		for k, item := range v {
+			if k == "cookie" {
+				masked[k] = maskPair(fmt.Sprint(item), "=")
+				continue
+			}
+			masked[k] = maskSecrets(k, item)
+		}
+		return masked
+	case []interface{}:
+		masked := []interface{}{}
+		for _, item := range v {
+			if s, ok := item.(string); ok && key == "custom_headers" && isSecretHeader(s) {
+				masked = append(masked, maskPair(s, ":"))
+				continue
+			}
+			masked = append(masked, maskSecrets(key, item))
+		}
+		return masked
+	case string:
+		if strings.Contains(key, "password") && v != "" {
+			return maskedSecret
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSecretHeader(header string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(header), ":")
+	for _, secret := range secretHeaders {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keep the name of a name/value pair and mask the value
+func maskPair(pair string, separator string) string {
+	name, _, found := strings.Cut(pair, separator)
+	if !found {
+		return maskedSecret
+	}
+	if separator == ":" {
+		return fmt.Sprintf("%s: %s", name, maskedSecret)
+	}
+	return fmt.Sprintf("%s%s%s", name, separator, maskedSecret)
+}
+
+func init() {
+	AuthCmd.AddCommand(AuthSetCmd)
+	AuthCmd.AddCommand(AuthShowCmd)
+
+	AuthSetCmd.Flags().String("http-user", "", "HTTP authentication username")
+	AuthSetCmd.Flags().String("http-password", "", "HTTP authentication password")
+	AuthSetCmd.Flags().String("http-domain", "", "Windows domain for NTLM authentication")
+	AuthSetCmd.Flags().Bool("disable-http-auth", false, "Disable HTTP authentication")
+	AuthSetCmd.Flags().String("login-url", "", "Login page URL for automatic form login")
+	AuthSetCmd.Flags().String("login-user", "", "Username for automatic form login")
+	AuthSetCmd.Flags().String("login-password", "", "Password for automatic form login")
+	AuthSetCmd.Flags().String("login-sequence", "", "Recorded login sequence (.lsr) file to upload")
+	AuthSetCmd.Flags().Bool("disable-login", false, "Disable the application login")
+	AuthSetCmd.Flags().StringArray("cookie", []string{}, "Session cookie as name=value, can be repeated")
+	AuthSetCmd.Flags().String("cookie-url", "", "URL the cookies apply to (defaults to the target address)")
+	AuthSetCmd.Flags().StringArray("header", []string{}, "Custom header as \"Name: value\", can be repeated")
+}
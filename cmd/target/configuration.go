@@ -0,0 +1,269 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/cmd/excludedHours"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+)
+
+// targetConfiguration holds the target configuration fields that can be declared in files.
+// Every field is optional, only the fields that are set are sent to the server.
+type targetConfiguration struct {
+	LimitCrawlerScope *bool           `json:"limit_crawler_scope,omitempty" yaml:"limit_crawler_scope,omitempty"`
+	Sensor            *bool           `json:"sensor,omitempty" yaml:"sensor,omitempty"`
+	ScanSpeed         *string         `json:"scan_speed,omitempty" yaml:"scan_speed,omitempty"`
+	CaseSensitive     *string         `json:"case_sensitive,omitempty" yaml:"case_sensitive,omitempty"`
+	Technologies      *[]string       `json:"technologies,omitempty" yaml:"technologies,omitempty"`
+	CustomHeaders     *[]string       `json:"custom_headers,omitempty" yaml:"custom_headers,omitempty"`
+	CustomCookies     *[]customCookie `json:"custom_cookies,omitempty" yaml:"custom_cookies,omitempty"`
+	ExcludedPaths     *[]string       `json:"excluded_paths,omitempty" yaml:"excluded_paths,omitempty"`
+	UserAgent         *string         `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	Debug             *bool           `json:"debug,omitempty" yaml:"debug,omitempty"`
+	Login             *loginConfig    `json:"login,omitempty" yaml:"login,omitempty"`
+	Authentication    *authConfig     `json:"authentication,omitempty" yaml:"authentication,omitempty"`
+	Proxy             *proxyConfig    `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	ExcludedHoursID   *string         `json:"excluded_hours_id,omitempty" yaml:"excluded_hours_id,omitempty"`
+
+	ClientCertificatePassword *string `json:"client_certificate_password,omitempty" yaml:"client_certificate_password,omitempty"`
+}
+
+type customCookie struct {
+	URL    string `json:"url" yaml:"url"`
+	Cookie string `json:"cookie" yaml:"cookie"`
+}
+
+// loginConfig is the application login, kind is none, automatic (form login) or sequence (recorded login sequence)
+type loginConfig struct {
+	Kind        string            `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Credentials *loginCredentials `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+type loginCredentials struct {
+	Enabled  *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// authConfig is the HTTP authentication (basic, digest or NTLM) of the site
+type authConfig struct {
+	Enabled  *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// proxyConfig is the proxy the scanner uses to reach the target
+type proxyConfig struct {
+	Enabled  *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Address  string `json:"address,omitempty" yaml:"address,omitempty"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// configChange describes the change of a single configuration field
+type configChange struct {
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to"`
+	Secret bool        `json:"secret,omitempty"`
+}
+
+// Convert a configuration to a map holding only the fields that are set
+func configurationMap(config *targetConfiguration) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if config == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// Replace an excluded hours profile given by name with its ID.
+// Resolved names are kept in cache so a manifest only looks them up once.
+func resolveConfigReferences(config map[string]interface{}, cache map[string]string) error {
+	profile, ok := config["excluded_hours_id"].(string)
+	if !ok || profile == "" {
+		return nil
+	}
+
+	if profileID, ok := cache[profile]; ok {
+		config["excluded_hours_id"] = profileID
+		return nil
+	}
+
+	profileID, err := excludedHours.ResolveExcludedHoursID(profile)
+	if err != nil {
+		return err
+	}
+	cache[profile] = profileID
+	config["excluded_hours_id"] = profileID
+	return nil
+}
+
+// Get the current configuration of a target
+func fetchTargetConfiguration(id string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/targets/%s/configuration", viper.GetString("URL"), id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting target configuration, status code: %d", resp.StatusCode)
+	}
+
+	var config map[string]interface{}
+	err = json.Unmarshal(body, &config)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return config, nil
+}
+
+// Send a partial configuration to a target
+func patchTargetConfiguration(id string, patch map[string]interface{}) (int, string) {
+	requestJson, err := json.Marshal(patch)
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/targets/%s/configuration", viper.GetString("URL"), id), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return 500, fmt.Sprintf("Error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Perform the request using the custom client
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return 500, fmt.Sprintf("Error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Sprintf("Error reading response body: %v", err)
+	}
+
+	return resp.StatusCode, string(body)
+}
+
+// Compute the fields of desired that differ from the current configuration, with secrets masked.
+// The server never returns passwords, so a field declaring one is always changed and marked as secret.
+func diffConfiguration(desired, current map[string]interface{}) map[string]configChange {
+	changes := make(map[string]configChange)
+	for key, value := range desired {
+		if !subsetEqual(value, current[key]) || declaresSecret(key, value) {
+			changes[key] = configChange{From: maskSecrets(key, current[key]), To: maskSecrets(key, value), Secret: declaresSecret(key, value)}
+		}
+	}
+	return changes
+}
+
+// Check whether a configuration value sets a password
+func declaresSecret(key string, value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if declaresSecret(k, item) {
+				return true
+			}
+		}
+	case string:
+		return strings.Contains(key, "password") && v != ""
+	}
+	return false
+}
+
+// Build the PATCH body for the desired fields. Nested objects are merged onto
+// the current values so that fields which are not declared are kept.
+// Passwords are never copied from the current values as the server does not return them.
+func mergeConfiguration(desired, current map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key, value := range desired {
+		patch[key] = mergeValue(value, current[key])
+	}
+	return patch
+}
+
+func mergeValue(desired, current interface{}) interface{} {
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		return desired
+	}
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return desired
+	}
+
+	merged := make(map[string]interface{})
+	for key, value := range currentMap {
+		if strings.Contains(key, "password") {
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range desiredMap {
+		merged[key] = mergeValue(value, currentMap[key])
+	}
+	return merged
+}
+
+// Check that every field of desired has the same value in current.
+// Secrets are never returned by the server, so they are not compared, see declaresSecret.
+func subsetEqual(desired, current interface{}) bool {
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(normalizeEmpty(desired), normalizeEmpty(current))
+	}
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for key, value := range desiredMap {
+		if strings.Contains(key, "password") {
+			continue
+		}
+		if !subsetEqual(value, currentMap[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Treat missing lists and empty lists as equal
+func normalizeEmpty(value interface{}) interface{} {
+	if list, ok := value.([]interface{}); ok && len(list) == 0 {
+		return nil
+	}
+	return value
+}
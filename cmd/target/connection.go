@@ -0,0 +1,83 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Check that a PKCS#12 client certificate can be opened with the password and is currently valid
+func validateClientCertificate(path string, password string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client certificate: %v", err)
+	}
+
+	_, cert, _, err := pkcs12.DecodeChain(data, password)
+	if err == pkcs12.ErrIncorrectPassword {
+		return nil, fmt.Errorf("incorrect password for client certificate %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding client certificate %s: %v", path, err)
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) {
+		return nil, fmt.Errorf("client certificate %s is not valid before %s", path, cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("client certificate %s expired on %s", path, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	return map[string]interface{}{
+		"subject":    cert.Subject.String(),
+		"issuer":     cert.Issuer.String(),
+		"not_after":  cert.NotAfter.Format(time.RFC3339),
+		"days_valid": int(cert.NotAfter.Sub(now).Hours() / 24),
+	}, nil
+}
+
+// Build the proxy configuration from the proxy flags, nil when no proxy flag is set
+func proxyOverrides(cmd *cobra.Command) (map[string]interface{}, error) {
+	disableProxy, _ := cmd.Flags().GetBool("disable-proxy")
+	if disableProxy {
+		return map[string]interface{}{"enabled": false}, nil
+	}
+
+	host, _ := cmd.Flags().GetString("proxy-host")
+	if host == "" {
+		for _, flag := range []string{"proxy-port", "proxy-protocol", "proxy-user", "proxy-password"} {
+			if cmd.Flags().Changed(flag) {
+				return nil, fmt.Errorf("--proxy-host is required with --%s", flag)
+			}
+		}
+		return nil, nil
+	}
+
+	protocol, _ := cmd.Flags().GetString("proxy-protocol")
+	port, _ := cmd.Flags().GetInt("proxy-port")
+	user, _ := cmd.Flags().GetString("proxy-user")
+	password, _ := cmd.Flags().GetString("proxy-password")
+
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid proxy port %d", port)
+	}
+
+	proxy := map[string]interface{}{
+		"enabled":  true,
+		"protocol": protocol,
+		"address":  host,
+		"port":     port,
+	}
+	if user != "" {
+		proxy["username"] = user
+		proxy["password"] = password
+	}
+	return proxy, nil
+}
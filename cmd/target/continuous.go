@@ -0,0 +1,195 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+type continuousScanBody struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ContinuousCmd represents the continuous command
+var ContinuousCmd = &cobra.Command{
+	Use:   "continuous",
+	Short: "Manage continuous scanning of targets",
+	Long: `Enable, disable or show continuous scanning of the targets taken from stdin, or of all the targets in a group. Example:
+
+echo "<TARGET-ID>" | acucli target continuous enable
+acucli target continuous disable --group <GROUP-ID>
+acucli target list | jq -r '.targets[].target_id' | acucli target continuous status`,
+}
+
+var continuousEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable continuous scanning",
+	Run: func(cmd *cobra.Command, args []string) {
+		runContinuous(cmd, func(id string) map[string]interface{} {
+			return setContinuousScan(id, true)
+		})
+	},
+}
+
+var continuousDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable continuous scanning",
+	Run: func(cmd *cobra.Command, args []string) {
+		runContinuous(cmd, func(id string) map[string]interface{} {
+			return setContinuousScan(id, false)
+		})
+	},
+}
+
+var continuousStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether continuous scanning is enabled",
+	Run: func(cmd *cobra.Command, args []string) {
+		runContinuous(cmd, func(id string) map[string]interface{} {
+			enabled, err := getContinuousScan(id)
+			if err != nil {
+				return map[string]interface{}{"status": "error", "error": err.Error()}
+			}
+			return map[string]interface{}{"enabled": enabled}
+		})
+	},
+}
+
+// Run an action on the targets of the group, or else on the targets taken from stdin
+func runContinuous(cmd *cobra.Command, action func(id string) map[string]interface{}) {
+	group, _ := cmd.Flags().GetString("group")
+
+	var ids []string
+	if group != "" {
+		groupID, err := resolver.Resolve(resolver.KindGroup, group)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		groupIDs, err := FetchGroupTargetIDs(groupID)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting targets of group")
+			return
+		}
+		ids = groupIDs
+	} else {
+		stdinIDs, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		ids = stdinIDs
+	}
+	if len(ids) == 0 {
+		jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+		return
+	}
+
+	results := make(map[string]interface{})
+	for _, id := range ids {
+		results[id] = action(id)
+	}
+
+	jsonoutput.OutputJSON(results)
+}
+
+// Get whether continuous scanning is enabled for a target
+func getContinuousScan(id string) (bool, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/targets/%s/continuous_scan", viper.GetString("URL"), id), nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("error getting continuous scan status, status code: %d", resp.StatusCode)
+	}
+
+	var status continuousScanBody
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return false, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return status.Enabled, nil
+}
+
+// Enable or disable continuous scanning for a target
+func setContinuousScan(id string, enabled bool) map[string]interface{} {
+	requestJson, err := json.Marshal(continuousScanBody{Enabled: enabled})
+	if err != nil {
+		return map[string]interface{}{"status": "error", "error": fmt.Sprintf("Error creating JSON request: %v", err)}
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/targets/%s/continuous_scan", viper.GetString("URL"), id), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return map[string]interface{}{"status": "error", "error": fmt.Sprintf("Error creating request: %v", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return map[string]interface{}{"status": "error", "error": fmt.Sprintf("Error making request: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return map[string]interface{}{"status": "error", "error": fmt.Sprintf("Error reading response body: %v", err)}
+	}
+
+	if resp.StatusCode >= 300 {
+		return map[string]interface{}{
+			"status":      "error",
+			"status_code": resp.StatusCode,
+			"response":    string(body),
+		}
+	}
+
+	return map[string]interface{}{
+		"status":      "ok",
+		"status_code": resp.StatusCode,
+		"enabled":     enabled,
+	}
+}
+
+func init() {
+	ContinuousCmd.PersistentFlags().StringP("group", "g", "", "Target group ID or name, applies to every target in the group instead of stdin")
+	completion.Register(ContinuousCmd, resolver.KindGroup, "group")
+
+	ContinuousCmd.AddCommand(continuousEnableCmd)
+	ContinuousCmd.AddCommand(continuousDisableCmd)
+	ContinuousCmd.AddCommand(continuousStatusCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// continuousCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// continuousCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -11,9 +11,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type ConfigResponseBody struct {
@@ -51,7 +51,11 @@ var GetConfigCmd = &cobra.Command{
 
 echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target getConfig`,
 	Run: func(cmd *cobra.Command, args []string) {
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
 		if input != nil {
 			getConfigRequest(input[0])
 		} else {
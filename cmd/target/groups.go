@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+)
+
+type groupTargetsBody struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// FetchGroupTargetIDs fetches the IDs of the targets in a target group
+func FetchGroupTargetIDs(groupID string) ([]string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/target_groups/%s/targets", viper.GetString("URL"), groupID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting targets of group %s, status code: %d", groupID, resp.StatusCode)
+	}
+
+	var response struct {
+		TargetIDList []string `json:"target_id_list"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return response.TargetIDList, nil
+}
+
+// Add and remove targets of a target group
+func patchGroupTargets(groupID string, add []string, remove []string) error {
+	if add == nil {
+		add = []string{}
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+
+	requestJson, err := json.Marshal(groupTargetsBody{Add: add, Remove: remove})
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/target_groups/%s/targets", viper.GetString("URL"), groupID), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error updating target group %s, status code: %d", groupID, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/importfile"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+type addResponseBody struct {
+	Targets []struct {
+		Address  string `json:"address"`
+		TargetID string `json:"target_id"`
+	} `json:"targets"`
+}
+
+// ImportCmd represents the import command
+var ImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import targets from Burp Suite, OpenAPI, Postman and HAR files",
+	Long: `Extracts the base URLs from an exported file, de-duplicates them and adds them as targets.
+The format is detected from the file, use --format to override it. Example:
+
+acucli target import --from openapi.yaml --gid=cd3db1f4-6275-478c-8830-8d96d37120f3 : Adds the API servers to the target group
+acucli target import --from burp.xml --criticality=normal --description="Burp sitemap"
+acucli target import --from collection.json --upload : Also uploads the collection to each target as a crawl seed
+acucli target import --from session.har --dry-run : Prints the extracted addresses without adding them`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		if from == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--from is required"), "Error")
+			return
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		addresses, format, err := importfile.ExtractAddresses(from, format)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading import file")
+			return
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dryRun {
+			jsonoutput.OutputJSON(map[string]interface{}{
+				"source":    from,
+				"format":    format,
+				"addresses": addresses,
+			})
+			return
+		}
+
+		groups := []string{}
+		inputGID, _ := cmd.Flags().GetString("gid")
+		if inputGID != "" {
+			groupID, err := resolver.Resolve(resolver.KindGroup, inputGID)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+				return
+			}
+			groups = append(groups, groupID)
+		}
+		description, _ := cmd.Flags().GetString("description")
+		criticalityFlag, _ := cmd.Flags().GetString("criticality")
+		criticality, err := parseCriticality(criticalityFlag)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+
+		targets := []Target{}
+		for _, address := range addresses {
+			targets = append(targets, Target{Address: address, Description: description, Type: "default", Criticality: criticality})
+		}
+
+		added, err := addTargets(targets, groups)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error adding targets")
+			return
+		}
+
+		result := map[string]interface{}{
+			"source":  from,
+			"format":  format,
+			"targets": added.Targets,
+		}
+
+		upload, _ := cmd.Flags().GetBool("upload")
+		if upload {
+			imports := make(map[string]interface{})
+			for _, t := range added.Targets {
+				uploadID, err := uploadTargetFile(t.TargetID, "imports", from)
+				if err != nil {
+					imports[t.TargetID] = map[string]string{"status": "error", "error": err.Error()}
+				} else {
+					imports[t.TargetID] = map[string]string{"status": "uploaded", "upload_id": uploadID}
+				}
+			}
+			result["imports"] = imports
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(result)
+	},
+}
+
+// Add targets and return the created targets with their IDs
+func addTargets(t []Target, groups []string) (addResponseBody, error) {
+	var added addResponseBody
+
+	postBody := PostBody{Targets: t, Groups: groups}
+	requestJson, err := json.Marshal(postBody)
+	if err != nil {
+		return added, fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/targets/add"), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return added, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return added, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return added, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return added, fmt.Errorf("error adding targets, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+	resolver.Invalidate(resolver.KindTarget)
+
+	err = json.Unmarshal(body, &added)
+	if err != nil {
+		return added, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return added, nil
+}
+
+func init() {
+	ImportCmd.Flags().StringP("from", "f", "", "File to import (OpenAPI, Burp Suite XML, Postman collection or HAR)")
+	ImportCmd.Flags().String("format", "", "File format (openapi, burp, postman, har), detected when empty")
+	ImportCmd.Flags().StringP("gid", "g", "", "Group ID (To assign the targets to the group)")
+	completion.Register(ImportCmd, resolver.KindGroup, "gid")
+	ImportCmd.Flags().StringP("description", "d", "", "Description of the created targets")
+	ImportCmd.Flags().String("criticality", defaultCriticality, "Criticality of the created targets: critical, high, normal or low")
+	ImportCmd.Flags().Bool("upload", false, "Upload the file to each target as an import to seed the crawler")
+	ImportCmd.Flags().Bool("dry-run", false, "Print the extracted addresses without adding targets")
+}
@@ -8,45 +8,49 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/scandata"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+type TargetListItem struct {
+	Address                  string `json:"address"`
+	Agents                   any    `json:"agents"`
+	ContinuousMode           bool   `json:"continuous_mode"`
+	Criticality              int    `json:"criticality"`
+	DefaultScanningProfileID string `json:"default_scanning_profile_id"`
+	DeletedAt                any    `json:"deleted_at"`
+	Description              string `json:"description"`
+	Fqdn                     string `json:"fqdn"`
+	FqdnHash                 string `json:"fqdn_hash"`
+	FqdnStatus               string `json:"fqdn_status"`
+	FqdnTmHash               string `json:"fqdn_tm_hash"`
+	IssueTrackerID           any    `json:"issue_tracker_id"`
+	LastScanDate             string `json:"last_scan_date"`
+	LastScanID               string `json:"last_scan_id"`
+	LastScanSessionID        string `json:"last_scan_session_id"`
+	LastScanSessionStatus    string `json:"last_scan_session_status"`
+	ManualIntervention       bool   `json:"manual_intervention"`
+	SeverityCounts           struct {
+		Critical int `json:"critical"`
+		High     int `json:"high"`
+		Info     int `json:"info"`
+		Low      int `json:"low"`
+		Medium   int `json:"medium"`
+	} `json:"severity_counts"`
+	TargetID     string `json:"target_id"`
+	Threat       int    `json:"threat"`
+	Type         any    `json:"type"`
+	Verification any    `json:"verification"`
+}
+
 type TargetList struct {
-	Targets []struct {
-		Address                  string `json:"address"`
-		Agents                   any    `json:"agents"`
-		ContinuousMode           bool   `json:"continuous_mode"`
-		Criticality              int    `json:"criticality"`
-		DefaultScanningProfileID string `json:"default_scanning_profile_id"`
-		DeletedAt                any    `json:"deleted_at"`
-		Description              string `json:"description"`
-		Fqdn                     string `json:"fqdn"`
-		FqdnHash                 string `json:"fqdn_hash"`
-		FqdnStatus               string `json:"fqdn_status"`
-		FqdnTmHash               string `json:"fqdn_tm_hash"`
-		IssueTrackerID           any    `json:"issue_tracker_id"`
-		LastScanDate             string `json:"last_scan_date"`
-		LastScanID               string `json:"last_scan_id"`
-		LastScanSessionID        string `json:"last_scan_session_id"`
-		LastScanSessionStatus    string `json:"last_scan_session_status"`
-		ManualIntervention       bool   `json:"manual_intervention"`
-		SeverityCounts           struct {
-			Critical int `json:"critical"`
-			High     int `json:"high"`
-			Info     int `json:"info"`
-			Low      int `json:"low"`
-			Medium   int `json:"medium"`
-		} `json:"severity_counts"`
-		TargetID     string `json:"target_id"`
-		Threat       int    `json:"threat"`
-		Type         any    `json:"type"`
-		Verification any    `json:"verification"`
-	} `json:"targets"`
+	Targets    []TargetListItem `json:"targets"`
 	Pagination struct {
 		Count      int    `json:"count"`
 		CursorHash string `json:"cursor_hash"`
@@ -94,6 +98,16 @@ var ListCmd = &cobra.Command{
 	},
 }
 
+// Fetch every target, following the pagination cursors.
+// The query is passed as the q parameter when not empty.
+func fetchAllTargets(query string) ([]TargetListItem, error) {
+	params := url.Values{}
+	if query != "" {
+		params.Set("q", query)
+	}
+	return scandata.FetchList[TargetListItem]("/targets", "targets", params)
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 
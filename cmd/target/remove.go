@@ -7,13 +7,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type RemovePostBody struct {
@@ -29,7 +30,11 @@ var RemoveCmd = &cobra.Command{
 	echo "9797f3aa-80f7-41a6-9e24-4926b35147cf" | acucli target remove : Removes the target
 	acucli target list | echo $(awk '{print $2}') | acucli target remove : Removes all targets`,
 	Run: func(cmd *cobra.Command, args []string) {
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
 		if input != nil {
 			makeDeleteRequest(input)
 		} else {
@@ -39,35 +44,52 @@ var RemoveCmd = &cobra.Command{
 }
 
 func makeDeleteRequest(ids []string) {
-	postBody := RemovePostBody{TargetIDList: ids}
-	requestJson, err := json.Marshal(postBody)
+	statusCode, _, err := DeleteTargets(ids)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
+		jsonoutput.OutputErrorAsJSON(err, "Error removing targets")
 		return
 	}
 
+	// Create a response object
+	response := map[string]interface{}{
+		"status_code": statusCode,
+		"status":      fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		"removed_ids": ids,
+	}
+
+	// Output only the JSON response
+	jsonoutput.OutputJSON(response)
+}
+
+// DeleteTargets removes targets and returns the status code and response body, the error is set
+// when the request could not be made
+func DeleteTargets(ids []string) (int, string, error) {
+	requestJson, err := json.Marshal(RemovePostBody{TargetIDList: ids})
+	if err != nil {
+		return 0, "", fmt.Errorf("error creating JSON request: %v", err)
+	}
+
 	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/targets/delete"), bytes.NewBuffer(requestJson))
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating request")
-		return
+		return 0, "", fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+
 	resp, err := httpclient.MyHTTPClient.Do(req)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error making request")
-		return
+		return 0, "", fmt.Errorf("error making request: %v", err)
 	}
 	defer resp.Body.Close()
 
-	// Create a response object
-	response := map[string]interface{}{
-		"status_code": resp.StatusCode,
-		"status":      resp.Status,
-		"removed_ids": ids,
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("error reading response body: %v", err)
+	}
+	if resp.StatusCode < 300 {
+		resolver.Invalidate(resolver.KindTarget)
 	}
 
-	// Output only the JSON response
-	jsonoutput.OutputJSON(response)
+	return resp.StatusCode, string(body), nil
 }
 
 func init() {
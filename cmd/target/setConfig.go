@@ -9,13 +9,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"gopkg.in/yaml.v3"
 )
 
 type configRequestBody struct {
@@ -49,24 +51,127 @@ type configRequestBody struct {
 var SetConfigCmd = &cobra.Command{
 	Use:   "setConfig",
 	Short: "Set scan config for target",
-	Long: `Takes scan config variables from the config yaml file and the target from stdin. Example
+	Long: `Takes the targets from stdin. Without --file or --set, the scan config variables are taken from the config yaml file. Example
 	
-	acucli targetGroup --id e3e5afcc-ee2e-431f-a8dc-9d894c93875d | cut -f2 | acucli target setConfig : Sets config for the targets in a target group
+	acucli targetGroup --id e3e5afcc-ee2e-431f-a8dc-9d894c93875d | jq -r '.target_id_list[]' | acucli target setConfig : Sets config for the targets in a target group
+
+	With --file and --set only the given fields are changed, the rest of the target's configuration is kept.
+	--set values are parsed as YAML, so lists are written as [a, b] and quoted values can hold commas.
+	An empty value (key=) unsets the field, it is sent as null.
+
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --file cfg.yaml : Sets the fields declared in cfg.yaml
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --set scan_speed=fast --set 'excluded_paths=[/logout, "/a,b"]'
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig -f cfg.yaml --set login.kind=none : --set overrides the file
+
+	Client certificate and proxy. The PKCS#12 file is checked locally (password and expiry) before it is uploaded.
+
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --client-cert client.p12 --client-cert-password secret
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --proxy-host 10.0.0.5 --proxy-port 3128 --proxy-user u --proxy-password p
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --disable-proxy
+
+	echo "5fac63fd-088c-4445-a2bf-a9f03f014832" | acucli target setConfig --excluded-hours "Office hours" : Attaches an excluded hours profile by name or ID
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
 		if input == nil || len(input) == 0 {
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no input provided"), "Error")
 			return
 		}
 
+		file, _ := cmd.Flags().GetString("file")
+		sets, _ := cmd.Flags().GetStringArray("set")
+
+		clientCert, _ := cmd.Flags().GetString("client-cert")
+		clientCertPassword, _ := cmd.Flags().GetString("client-cert-password")
+
+		var overrides map[string]interface{}
+		if file != "" || len(sets) > 0 {
+			var err error
+			overrides, err = loadConfigurationOverrides(file, sets)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error reading configuration")
+				return
+			}
+		}
+
+		if cmd.Flags().Changed("excluded-hours") {
+			if overrides == nil {
+				overrides = make(map[string]interface{})
+			}
+			profile, _ := cmd.Flags().GetString("excluded-hours")
+			if strings.EqualFold(profile, "none") || profile == "" {
+				overrides["excluded_hours_id"] = nil
+			} else {
+				overrides["excluded_hours_id"] = profile
+			}
+		}
+		if overrides != nil {
+			if err := resolveConfigReferences(overrides, map[string]string{}); err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error")
+				return
+			}
+		}
+
+		proxy, err := proxyOverrides(cmd)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+		if proxy != nil {
+			if overrides == nil {
+				overrides = make(map[string]interface{})
+			}
+			overrides["proxy"] = proxy
+		}
+
+		// Validate the certificate before anything is changed on the server
+		var certInfo map[string]interface{}
+		if clientCert != "" {
+			certInfo, err = validateClientCertificate(clientCert, clientCertPassword)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error validating client certificate")
+				return
+			}
+			if overrides == nil {
+				overrides = make(map[string]interface{})
+			}
+			overrides["client_certificate_password"] = clientCertPassword
+		} else if cmd.Flags().Changed("client-cert-password") {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--client-cert-password requires --client-cert"), "Error")
+			return
+		}
+
 		results := make(map[string]interface{})
 		for _, id := range input {
-			statusCode, responseBody := setConfigRequest(id)
-			results[id] = map[string]interface{}{
-				"status_code": statusCode,
-				"response":    responseBody,
+			result := make(map[string]interface{})
+			results[id] = result
+
+			if clientCert != "" {
+				uploadID, err := uploadTargetFile(id, "client_certificate", clientCert)
+				if err != nil {
+					result["status"] = "error"
+					result["error"] = fmt.Sprintf("Error uploading client certificate: %v", err)
+					continue
+				}
+				result["client_certificate"] = map[string]interface{}{
+					"upload_id":   uploadID,
+					"certificate": certInfo,
+				}
 			}
+
+			var statusCode int
+			var responseBody string
+			if overrides != nil {
+				statusCode, responseBody = mergeConfigRequest(id, overrides)
+			} else {
+				statusCode, responseBody = setConfigRequest(id)
+			}
+			result["status_code"] = statusCode
+			result["response"] = responseBody
 		}
 
 		// Output only the JSON response
@@ -102,6 +207,124 @@ func setConfigRequest(id string) (int, string) {
 	return resp.StatusCode, string(body)
 }
 
+// ConfigureTargets sets the configuration fields from a YAML file and key=value overrides on each target
+// and returns the result per target
+func ConfigureTargets(ids []string, file string, sets []string) (map[string]interface{}, error) {
+	overrides, err := loadConfigurationOverrides(file, sets)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveConfigReferences(overrides, map[string]string{}); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]interface{})
+	for _, id := range ids {
+		statusCode, responseBody := mergeConfigRequest(id, overrides)
+		results[id] = map[string]interface{}{
+			"status_code": statusCode,
+			"response":    responseBody,
+		}
+	}
+	return results, nil
+}
+
+// Merge the overrides onto the target's current configuration and send only the overridden fields
+func mergeConfigRequest(id string, overrides map[string]interface{}) (int, string) {
+	current, err := fetchTargetConfiguration(id)
+	if err != nil {
+		return 500, fmt.Sprintf("Error getting current configuration: %v", err)
+	}
+
+	return patchTargetConfiguration(id, mergeConfiguration(overrides, current))
+}
+
+// Build the configuration fields to set from a YAML file and key=value overrides
+func loadConfigurationOverrides(file string, sets []string) (map[string]interface{}, error) {
+	overrides := make(map[string]interface{})
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var config targetConfiguration
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error parsing %s: %v", file, err)
+		}
+
+		overrides, err = configurationMap(&config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Fields given as key= are sent as null, which the round trip below would drop
+	unset := make(map[string][]string)
+	for _, set := range sets {
+		key, rawValue, found := strings.Cut(set, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --set %q, expected key=value", set)
+		}
+
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(rawValue), &value); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+		if value == nil {
+			unset[key] = strings.Split(key, ".")
+		} else {
+			delete(unset, key)
+		}
+
+		setConfigPath(overrides, strings.Split(key, "."), value)
+	}
+
+	// Round trip through the configuration model to reject unknown keys and wrong types
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, err
+	}
+	var config targetConfiguration
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %v", err)
+	}
+
+	overrides, err = configurationMap(&config)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range unset {
+		setConfigPath(overrides, path, nil)
+	}
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("no configuration fields given")
+	}
+
+	return overrides, nil
+}
+
+// Set a value in nested maps following a dotted key path
+func setConfigPath(config map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 1 {
+		config[path[0]] = value
+		return
+	}
+
+	child, ok := config[path[0]].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		config[path[0]] = child
+	}
+	setConfigPath(child, path[1:], value)
+}
+
 func getConfigAsSlice(key string) []string {
 	configValue := viper.GetString(key)
 	if configValue == "" {
@@ -144,6 +367,18 @@ func defineConfig() configRequestBody {
 }
 
 func init() {
+	SetConfigCmd.Flags().StringP("file", "f", "", "YAML file with the configuration fields to set")
+	SetConfigCmd.Flags().StringArray("set", []string{}, "Configuration field to set as key=value, key= unsets it, can be repeated (e.g. login.kind=none)")
+	SetConfigCmd.Flags().String("client-cert", "", "PKCS#12 (.p12/.pfx) client certificate to upload")
+	SetConfigCmd.Flags().String("client-cert-password", "", "Password of the client certificate")
+	SetConfigCmd.Flags().String("proxy-protocol", "http", "Proxy protocol")
+	SetConfigCmd.Flags().String("proxy-host", "", "Proxy host, enables the proxy")
+	SetConfigCmd.Flags().Int("proxy-port", 8080, "Proxy port")
+	SetConfigCmd.Flags().String("proxy-user", "", "Proxy username")
+	SetConfigCmd.Flags().String("proxy-password", "", "Proxy password")
+	SetConfigCmd.Flags().Bool("disable-proxy", false, "Disable the proxy")
+	SetConfigCmd.Flags().String("excluded-hours", "", "Excluded hours profile ID or name, none to detach the profile")
+	SetConfigCmd.MarkFlagsMutuallyExclusive("proxy-host", "disable-proxy")
 
 	// Here you will define your flags and configuration settings.
 
@@ -11,8 +11,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type responseBody struct {
@@ -59,7 +61,12 @@ var TargetCmd = &cobra.Command{
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target ID is required"), "Error")
 			return
 		}
-		GetTargetRequest(id)
+		targetID, err := resolver.Resolve(resolver.KindTarget, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target")
+			return
+		}
+		GetTargetRequest(targetID)
 	},
 }
 
@@ -96,8 +103,41 @@ func GetTargetRequest(id string) {
 	jsonoutput.OutputRawJSON(body)
 }
 
+// Fetch a single target
+func fetchTarget(id string) (responseBody, error) {
+	var target responseBody
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s%s", viper.GetString("URL"), "/targets/", id), nil)
+	if err != nil {
+		return target, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return target, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return target, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return target, fmt.Errorf("target does not exist, status code: %d", resp.StatusCode)
+	}
+
+	err = json.Unmarshal(body, &target)
+	if err != nil {
+		return target, fmt.Errorf("error parsing response: %v", err)
+	}
+
+	return target, nil
+}
+
 func init() {
-	TargetCmd.Flags().StringVarP(&id, "id", "", "", "Target ID")
+	TargetCmd.Flags().StringVarP(&id, "id", "", "", "Target ID or address")
+	completion.Register(TargetCmd, resolver.KindTarget, "id")
 	TargetCmd.MarkFlagRequired("id")
 
 	TargetCmd.AddCommand(ListCmd)
@@ -105,6 +145,12 @@ func init() {
 	TargetCmd.AddCommand(RemoveCmd)
 	TargetCmd.AddCommand(GetConfigCmd)
 	TargetCmd.AddCommand(SetConfigCmd)
+	TargetCmd.AddCommand(ImportCmd)
+	TargetCmd.AddCommand(ApplyCmd)
+	TargetCmd.AddCommand(AuthCmd)
+	TargetCmd.AddCommand(VerifyCmd)
+	TargetCmd.AddCommand(ContinuousCmd)
+	TargetCmd.AddCommand(UpdateCmd)
 
 	// Here you will define your flags and configuration settings.
 
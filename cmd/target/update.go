@@ -0,0 +1,230 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"gopkg.in/yaml.v3"
+)
+
+// Criticality values used by the server
+var criticalityLevels = map[string]int{
+	"critical": 30,
+	"high":     20,
+	"normal":   10,
+	"low":      0,
+}
+
+// Criticality of targets created without one, by add, import and apply
+const defaultCriticality = "critical"
+
+// criticalityLevel is a criticality given either by name or by value in a manifest
+type criticalityLevel int
+
+func (c *criticalityLevel) UnmarshalYAML(value *yaml.Node) error {
+	level, err := parseCriticality(value.Value)
+	if err != nil {
+		return err
+	}
+	*c = criticalityLevel(level)
+	return nil
+}
+
+// Parse a criticality name (critical, high, normal, low) or value (30, 20, 10, 0)
+func parseCriticality(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if level, ok := criticalityLevels[value]; ok {
+		return level, nil
+	}
+	if level, err := strconv.Atoi(value); err == nil {
+		for _, known := range criticalityLevels {
+			if level == known {
+				return level, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid criticality %q (critical, high, normal, low)", value)
+}
+
+// UpdateCmd represents the update command
+var UpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update the criticality, description or type of targets",
+	Long: `Updates the targets taken from stdin, or the targets matching a --filter query. Only the given fields are changed. Example:
+
+echo "<TARGET-ID>" | acucli target update --criticality high --description "Payment API"
+acucli target update --filter "text_search:*staging" --criticality low --dry-run : Preview the changes
+acucli target update --filter "criticality:30" --criticality normal --parallel 10`,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, _ := cmd.Flags().GetString("filter")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+
+		body := make(map[string]interface{})
+		if cmd.Flags().Changed("criticality") {
+			value, _ := cmd.Flags().GetString("criticality")
+			criticality, err := parseCriticality(value)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error")
+				return
+			}
+			body["criticality"] = criticality
+		}
+		if cmd.Flags().Changed("description") {
+			body["description"], _ = cmd.Flags().GetString("description")
+		}
+		if cmd.Flags().Changed("type") {
+			body["type"], _ = cmd.Flags().GetString("type")
+		}
+		if len(body) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("nothing to update, use --criticality, --description or --type"), "Error")
+			return
+		}
+		if parallel < 1 {
+			parallel = 1
+		}
+
+		// Targets known from the filter query, stdin targets are fetched when needed
+		known := make(map[string]TargetListItem)
+		var ids []string
+		if filter != "" {
+			targets, err := fetchAllTargets(filter)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error listing targets")
+				return
+			}
+			for _, t := range targets {
+				known[t.TargetID] = t
+				ids = append(ids, t.TargetID)
+			}
+		} else {
+			stdinIDs, err := resolver.ReadStdin(resolver.KindTarget)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+				return
+			}
+			ids = stdinIDs
+			if len(ids) == 0 {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+				return
+			}
+		}
+
+		results := updateTargets(ids, body, known, dryRun, parallel)
+
+		jsonoutput.OutputJSON(map[string]interface{}{
+			"dry_run": dryRun,
+			"update":  body,
+			"results": results,
+		})
+	},
+}
+
+// PATCH the targets with a bounded number of parallel requests.
+// With dryRun only the changes against the current values are computed.
+func updateTargets(ids []string, body map[string]interface{}, known map[string]TargetListItem, dryRun bool, parallel int) map[string]interface{} {
+	results := make(map[string]interface{})
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				var result map[string]interface{}
+				if dryRun {
+					result = previewTargetUpdate(id, body, known)
+				} else if err := patchTarget(id, body); err != nil {
+					result = map[string]interface{}{"status": "error", "error": err.Error()}
+				} else {
+					result = map[string]interface{}{"status": "updated"}
+				}
+
+				mutex.Lock()
+				results[id] = result
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+func previewTargetUpdate(id string, body map[string]interface{}, known map[string]TargetListItem) map[string]interface{} {
+	current := make(map[string]interface{})
+	address := ""
+	if t, ok := known[id]; ok {
+		address = t.Address
+		current["criticality"] = t.Criticality
+		current["description"] = t.Description
+		current["type"] = t.Type
+	} else {
+		t, err := fetchTarget(id)
+		if err != nil {
+			return map[string]interface{}{"status": "error", "error": err.Error()}
+		}
+		address = t.Address
+		current["criticality"] = t.Criticality
+		current["description"] = t.Description
+		current["type"] = t.Type
+	}
+
+	fields := make([]string, 0, len(body))
+	for field := range body {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := make(map[string]configChange)
+	for _, field := range fields {
+		if fmt.Sprint(current[field]) != fmt.Sprint(body[field]) {
+			changes[field] = configChange{From: current[field], To: body[field]}
+		}
+	}
+
+	status := "unchanged"
+	if len(changes) > 0 {
+		status = "would update"
+	}
+	return map[string]interface{}{
+		"address": address,
+		"status":  status,
+		"changes": changes,
+	}
+}
+
+func init() {
+	UpdateCmd.Flags().String("criticality", "", "Criticality: critical, high, normal or low")
+	UpdateCmd.Flags().String("description", "", "Description of the targets")
+	UpdateCmd.Flags().String("type", "", "Type of the targets (e.g. default)")
+	UpdateCmd.Flags().String("filter", "", "Update the targets matching this query instead of stdin (e.g. text_search:*staging)")
+	UpdateCmd.Flags().Bool("dry-run", false, "Show the changes without updating the targets")
+	UpdateCmd.Flags().Int("parallel", 5, "Number of parallel requests")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// updateCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// updateCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
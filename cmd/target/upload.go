@@ -0,0 +1,103 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+)
+
+type uploadRequestBody struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+type uploadResponseBody struct {
+	UploadID  string `json:"upload_id"`
+	UploadURL string `json:"upload_url"`
+}
+
+// Upload a file to one of the target configuration upload endpoints
+// (imports, login_sequence, client_certificate) and return the upload ID
+func uploadTargetFile(targetID string, endpoint string, filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading file: %v", err)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("file %s is empty", filePath)
+	}
+
+	fileName := filepath.Base(filePath)
+	requestJson, err := json.Marshal(uploadRequestBody{Name: fileName, Size: len(data)})
+	if err != nil {
+		return "", fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	// Step 1: Ask for an upload URL
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/targets/%s/configuration/%s", viper.GetString("URL"), targetID, endpoint), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("error requesting upload, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var upload uploadResponseBody
+	err = json.Unmarshal(body, &upload)
+	if err != nil {
+		return "", fmt.Errorf("error parsing response: %v", err)
+	}
+	if upload.UploadURL == "" {
+		return "", fmt.Errorf("no upload URL in response")
+	}
+
+	// Step 2: Send the file content to the upload URL
+	baseURL := viper.GetString("URL")
+	if filepath.Base(baseURL) == "v1" && strings.HasPrefix(upload.UploadURL, "/api/v1") {
+		baseURL = strings.TrimSuffix(baseURL, "/api/v1")
+	}
+
+	uploadReq, err := http.NewRequest("POST", fmt.Sprintf("%s%s", baseURL, upload.UploadURL), bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("error creating upload request: %v", err)
+	}
+	uploadReq.Header.Set("Content-Type", "application/octet-stream")
+	uploadReq.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	uploadReq.Header.Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(data)-1, len(data)))
+
+	uploadResp, err := httpclient.MyHTTPClient.Do(uploadReq)
+	if err != nil {
+		return "", fmt.Errorf("error uploading file: %v", err)
+	}
+	defer uploadResp.Body.Close()
+
+	if uploadResp.StatusCode >= 300 {
+		return "", fmt.Errorf("error uploading file, status code: %d", uploadResp.StatusCode)
+	}
+
+	return upload.UploadID, nil
+}
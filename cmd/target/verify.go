@@ -0,0 +1,358 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package target
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// Verification methods accepted by the verify endpoint
+var verificationMethods = []string{"file", "dns", "meta"}
+
+type verificationToken struct {
+	Token string `json:"token"`
+}
+
+// verificationMethod is one way to prove the ownership of a target, with the values to publish as given by the server
+type verificationMethod struct {
+	Method      string `json:"method"`
+	FileName    string `json:"file_name,omitempty"`
+	FilePath    string `json:"file_path,omitempty"`
+	FileContent string `json:"file_content,omitempty"`
+	RecordName  string `json:"record_name,omitempty"`
+	RecordType  string `json:"record_type,omitempty"`
+	RecordValue string `json:"record_value,omitempty"`
+	MetaTag     string `json:"meta_tag,omitempty"`
+}
+
+type verificationMethodList struct {
+	Methods []verificationMethod `json:"methods"`
+}
+
+// VerifyCmd represents the verify command
+var VerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the ownership of targets",
+	Long: `Requests a verification token for the targets taken from stdin, prints the instructions of each verification
+method offered by the server (the file name, path and content, the DNS record name and value, the meta tag) and
+polls until the targets are verified. With --webroot the verification file is written to its path under the web
+root (file method only). Example:
+
+echo "<TARGET-ID>" | acucli target verify --no-wait : Print the instructions only
+echo "<TARGET-ID>" | acucli target verify --webroot /var/www/html : Write the verification file and wait for the target to be verified
+echo "<TARGET-ID>" | acucli target verify --method dns --timeout 1800 : Wait for the DNS TXT record to be picked up`,
+	Run: func(cmd *cobra.Command, args []string) {
+		method, _ := cmd.Flags().GetString("method")
+		webroot, _ := cmd.Flags().GetString("webroot")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		interval, _ := cmd.Flags().GetInt("interval")
+
+		if !stringInList(method, verificationMethods) {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid method %q (file, dns, meta)", method), "Error")
+			return
+		}
+		if interval < 1 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--interval must be at least 1 second"), "Error")
+			return
+		}
+		if webroot != "" {
+			if method != "file" {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("--webroot only applies to --method file"), "Error")
+				return
+			}
+			if info, err := os.Stat(webroot); err != nil || !info.IsDir() {
+				jsonoutput.OutputErrorAsJSON(fmt.Errorf("web root %s is not a directory", webroot), "Error")
+				return
+			}
+		}
+
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		if input == nil || len(input) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+			return
+		}
+
+		results := make(map[string]interface{})
+		pending := []string{}
+		for _, id := range input {
+			result := make(map[string]interface{})
+			results[id] = result
+
+			target, err := fetchTarget(id)
+			if err != nil {
+				result["status"] = "error"
+				result["error"] = err.Error()
+				continue
+			}
+			result["address"] = target.Address
+
+			if isVerified(target.Verification) {
+				result["status"] = "verified"
+				result["verification"] = target.Verification
+				continue
+			}
+
+			token, err := requestVerificationToken(id)
+			if err != nil {
+				result["status"] = "error"
+				result["error"] = err.Error()
+				continue
+			}
+			result["token"] = token
+
+			methods, err := fetchVerificationMethods(id)
+			if err != nil {
+				result["status"] = "error"
+				result["error"] = err.Error()
+				continue
+			}
+			instructions := make(map[string]verificationMethod)
+			for _, m := range methods {
+				instructions[m.Method] = m
+			}
+			result["instructions"] = instructions
+
+			chosen, ok := instructions[method]
+			if !ok {
+				result["status"] = "error"
+				result["error"] = fmt.Sprintf("the server does not offer the %s verification method for this target", method)
+				continue
+			}
+
+			if webroot != "" {
+				path, err := verificationFilePath(webroot, chosen)
+				if err != nil {
+					result["status"] = "error"
+					result["error"] = err.Error()
+					continue
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					result["status"] = "error"
+					result["error"] = fmt.Sprintf("Error creating verification file directory: %v", err)
+					continue
+				}
+				if err := os.WriteFile(path, []byte(chosen.FileContent), 0644); err != nil {
+					result["status"] = "error"
+					result["error"] = fmt.Sprintf("Error writing verification file: %v", err)
+					continue
+				}
+				result["file_written"] = path
+			}
+
+			result["status"] = "pending"
+			pending = append(pending, id)
+		}
+
+		if !noWait && len(pending) > 0 {
+			waitForVerification(pending, method, timeout, interval, results)
+		}
+
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+// Request a verification token for a target
+func requestVerificationToken(id string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/targets/%s/verification_token", viper.GetString("URL"), id), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error getting verification token, status code: %d", resp.StatusCode)
+	}
+
+	var token verificationToken
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return "", fmt.Errorf("error parsing response: %v", err)
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("server returned an empty verification token")
+	}
+
+	return token.Token, nil
+}
+
+// Get the verification methods of a target with the values to publish for each
+func fetchVerificationMethods(id string) ([]verificationMethod, error) {
+	body, err := httpclient.Get(fmt.Sprintf("/targets/%s/verification_methods", id))
+	if err != nil {
+		return nil, err
+	}
+
+	var list verificationMethodList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("error parsing verification methods: %v", err)
+	}
+	if len(list.Methods) == 0 {
+		return nil, fmt.Errorf("server returned no verification methods")
+	}
+
+	return list.Methods, nil
+}
+
+// Path of the verification file under the web root. The file is served at file_path (or /file_name), which
+// must stay inside the web root.
+func verificationFilePath(webroot string, file verificationMethod) (string, error) {
+	if file.FileContent == "" {
+		return "", fmt.Errorf("server returned no content for the verification file")
+	}
+	urlPath := file.FilePath
+	if urlPath == "" {
+		if file.FileName == "" || filepath.Base(file.FileName) != file.FileName {
+			return "", fmt.Errorf("invalid verification file name %q", file.FileName)
+		}
+		urlPath = "/" + file.FileName
+	}
+
+	relative := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(urlPath, "/")))
+	if relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) || filepath.IsAbs(relative) {
+		return "", fmt.Errorf("invalid verification file path %q", urlPath)
+	}
+	return filepath.Join(webroot, relative), nil
+}
+
+// Ask the server to check the verification of a target with the given method
+func triggerVerification(id string, method string) error {
+	requestJson, err := json.Marshal(map[string]string{"method": method})
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/targets/%s/verify", viper.GetString("URL"), id), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// A failed check is reported as a client error, the target is polled again
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("error verifying target, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Poll the pending targets until they are verified or the timeout is reached
+func waitForVerification(ids []string, method string, timeoutSeconds int, intervalSeconds int, results map[string]interface{}) {
+	startTime := time.Now()
+	timeout := time.Duration(timeoutSeconds) * time.Second
+
+	pending := ids
+	for len(pending) > 0 {
+		remaining := []string{}
+		for _, id := range pending {
+			result := results[id].(map[string]interface{})
+
+			if err := triggerVerification(id, method); err != nil {
+				result["last_error"] = err.Error()
+			}
+
+			target, err := fetchTarget(id)
+			if err != nil {
+				result["last_error"] = err.Error()
+				remaining = append(remaining, id)
+				continue
+			}
+
+			if isVerified(target.Verification) {
+				result["status"] = "verified"
+				result["verification"] = target.Verification
+				delete(result, "last_error")
+				continue
+			}
+			remaining = append(remaining, id)
+		}
+		pending = remaining
+
+		if len(pending) == 0 {
+			return
+		}
+		if time.Since(startTime) > timeout {
+			for _, id := range pending {
+				results[id].(map[string]interface{})["status"] = "timeout"
+			}
+			return
+		}
+
+		time.Sleep(time.Duration(intervalSeconds) * time.Second)
+	}
+}
+
+// The verification field is null (or empty) until the target is verified
+func isVerified(verification any) bool {
+	switch v := verification.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case bool:
+		return v
+	}
+	return true
+}
+
+func stringInList(value string, list []string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	VerifyCmd.Flags().String("method", "file", "Verification method to check: file, dns or meta")
+	VerifyCmd.Flags().String("webroot", "", "Web root directory to write the verification file to")
+	VerifyCmd.Flags().Bool("no-wait", false, "Print the instructions without waiting for the verification")
+	VerifyCmd.Flags().IntP("timeout", "i", 600, "Timeout in seconds to wait for the verification")
+	VerifyCmd.Flags().Int("interval", 10, "Seconds between verification checks")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// verifyCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// verifyCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
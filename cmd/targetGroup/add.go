@@ -15,6 +15,7 @@ import (
 	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type PostBody struct {
@@ -88,6 +89,7 @@ var AddCmd = &cobra.Command{
 					results[targetGroupName] = response
 				}
 			}
+			resolver.Invalidate(resolver.KindGroup)
 
 			// Output only the JSON response
 			jsonoutput.OutputJSON(results)
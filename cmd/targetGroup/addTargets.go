@@ -12,9 +12,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type postBody struct {
@@ -35,20 +36,31 @@ var AddTargetsCmd = &cobra.Command{
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
 			return
 		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
 
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
 		if input != nil && len(input) > 0 {
 			pBody := postBody{}
 			pBody.Add = input
 			pBody.Remove = []string{}
-			addTargets(pBody, id)
+			updateGroupTargets(pBody, id)
 		} else {
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
 		}
 	},
 }
 
-func addTargets(pBody postBody, id string) {
+// Send the add and remove lists of a target group and output the result
+func updateGroupTargets(pBody postBody, id string) {
 	requestJson, err := json.Marshal(pBody)
 	if err != nil {
 		jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
@@ -77,10 +89,15 @@ func addTargets(pBody postBody, id string) {
 
 	// Create a response object
 	response := map[string]interface{}{
-		"status_code":   resp.StatusCode,
-		"status":        resp.Status,
-		"added_targets": pBody.Add,
-		"group_id":      id,
+		"status_code": resp.StatusCode,
+		"status":      resp.Status,
+		"group_id":    id,
+	}
+	if len(pBody.Add) > 0 {
+		response["added_targets"] = pBody.Add
+	}
+	if len(pBody.Remove) > 0 {
+		response["removed_targets"] = pBody.Remove
 	}
 
 	// If there's a response body, include it
@@ -98,7 +115,8 @@ func addTargets(pBody postBody, id string) {
 }
 
 func init() {
-	AddTargetsCmd.Flags().StringVarP(&id, "id", "", "", "Group Target ID")
+	AddTargetsCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(AddTargetsCmd, resolver.KindGroup, "id")
 	AddTargetsCmd.MarkFlagRequired("id")
 	// Here you will define your flags and configuration settings.
 
@@ -12,9 +12,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tosbaa/acucli/helpers/filehelper"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type RemovePostBody struct {
@@ -31,7 +31,11 @@ var RemoveCmd = &cobra.Command{
 	cat toremove.txt | acucli targetGroup remove : Removes multiple
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		input := filehelper.ReadStdin()
+		input, err := resolver.ReadStdin(resolver.KindGroup)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target groups")
+			return
+		}
 		if input != nil && len(input) > 0 {
 			makeDeleteRequest(input)
 		} else {
@@ -41,47 +45,26 @@ var RemoveCmd = &cobra.Command{
 }
 
 func makeDeleteRequest(ids []string) {
-	postBody := RemovePostBody{TargetGroupIDList: ids}
-	requestJson, err := json.Marshal(postBody)
-	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
-		return
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/target_groups/delete"), bytes.NewBuffer(requestJson))
+	statusCode, body, err := DeleteTargetGroups(ids)
 	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error creating request")
-		return
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := httpclient.MyHTTPClient.Do(req)
-	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error making request")
-		return
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
+		jsonoutput.OutputErrorAsJSON(err, "Error removing target groups")
 		return
 	}
 
 	// Create a response object
 	response := map[string]interface{}{
-		"status_code": resp.StatusCode,
-		"status":      resp.Status,
+		"status_code": statusCode,
+		"status":      fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
 		"removed_ids": ids,
 	}
 
 	// If there's a response body, include it
 	if len(body) > 0 {
 		var jsonBody interface{}
-		if err := json.Unmarshal(body, &jsonBody); err == nil {
+		if err := json.Unmarshal([]byte(body), &jsonBody); err == nil {
 			response["response_body"] = jsonBody
 		} else {
-			response["response_body"] = string(body)
+			response["response_body"] = body
 		}
 	}
 
@@ -89,6 +72,37 @@ func makeDeleteRequest(ids []string) {
 	jsonoutput.OutputJSON(response)
 }
 
+// DeleteTargetGroups removes target groups, keeping their targets, and returns the status code and
+// response body, the error is set when the request could not be made
+func DeleteTargetGroups(ids []string) (int, string, error) {
+	requestJson, err := json.Marshal(RemovePostBody{TargetGroupIDList: ids})
+	if err != nil {
+		return 0, "", fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", viper.GetString("URL"), "/target_groups/delete"), bytes.NewBuffer(requestJson))
+	if err != nil {
+		return 0, "", fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.MyHTTPClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("error reading response body: %v", err)
+	}
+	if resp.StatusCode < 300 {
+		resolver.Invalidate(resolver.KindGroup)
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 
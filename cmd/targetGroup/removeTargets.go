@@ -0,0 +1,64 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// removeTargetsCmd represents the removeTargets command
+var RemoveTargetsCmd = &cobra.Command{
+	Use:   "removeTargets",
+	Short: "Remove targets from a target group",
+	Long: `Removes the targets from stdin from the target group given with the id flag. The targets themselves are not deleted. Example:
+	cat targets.txt | acucli targetGroup removeTargets --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 : Removes the targets in the file from the group
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		if input != nil && len(input) > 0 {
+			pBody := postBody{}
+			pBody.Add = []string{}
+			pBody.Remove = input
+			updateGroupTargets(pBody, id)
+		} else {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+		}
+	},
+}
+
+func init() {
+	RemoveTargetsCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(RemoveTargetsCmd, resolver.KindGroup, "id")
+	RemoveTargetsCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// removeTargetsCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// removeTargetsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
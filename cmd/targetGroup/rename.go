@@ -0,0 +1,103 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// renameCmd represents the rename command
+var RenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename a target group",
+	Long: `Renames the target group given with the id flag. Example:
+	acucli targetGroup rename --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 --name production : Renames the group to production
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--name is required"), "Error")
+			return
+		}
+
+		requestJson, err := json.Marshal(PostBody{Name: name})
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating JSON request")
+			return
+		}
+
+		req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/target_groups/%s", viper.GetString("URL"), id), bytes.NewBuffer(requestJson))
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error creating request")
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpclient.MyHTTPClient.Do(req)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error making request")
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading response body")
+			return
+		}
+
+		resolver.Invalidate(resolver.KindGroup)
+
+		response := map[string]interface{}{
+			"status_code": resp.StatusCode,
+			"status":      resp.Status,
+			"group_id":    id,
+			"name":        name,
+		}
+		if len(body) > 0 {
+			response["response_body"] = string(body)
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(response)
+	},
+}
+
+func init() {
+	RenameCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(RenameCmd, resolver.KindGroup, "id")
+	RenameCmd.Flags().String("name", "", "New name of the target group")
+	RenameCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// renameCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// renameCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
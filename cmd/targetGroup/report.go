@@ -0,0 +1,68 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/cmd/report"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// reportCmd represents the report command
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a report of a target group",
+	Long: `Generates a report covering the vulnerabilities of every target in the target group. Example:
+	acucli targetGroup report --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 --template "Executive Summary" : Generates an executive summary of the group
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+
+		template, _ := cmd.Flags().GetString("template")
+		templateID, err := report.ResolveTemplateID(template)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving report template")
+			return
+		}
+
+		description, _ := cmd.Flags().GetString("description")
+		if description == "" {
+			description = fmt.Sprintf("Target group %s", id)
+		}
+
+		report.GenerateReport(templateID, description, "groups", []string{id})
+	},
+}
+
+func init() {
+	ReportCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	ReportCmd.Flags().StringP("template", "t", "11111111-1111-1111-1111-111111111126", "Report template ID or name")
+	completion.Register(ReportCmd, resolver.KindGroup, "id")
+	completion.Register(ReportCmd, resolver.KindReportTemplate, "template")
+	ReportCmd.Flags().StringP("description", "d", "", "Report description")
+	ReportCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// reportCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// reportCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
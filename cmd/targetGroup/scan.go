@@ -0,0 +1,86 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/cmd/scan"
+	"github.com/tosbaa/acucli/cmd/target"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// scanCmd represents the scan command
+var ScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Scan every target of a target group",
+	Long: `Starts a scan with the given scan profile for every target in the target group. Example:
+	acucli targetGroup scan --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 --profile "Full Scan" : Full scan of every target in the group
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+		profile, _ := cmd.Flags().GetString("profile")
+		if profile == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("scan profile ID is required"), "Error")
+			return
+		}
+		profile, err = resolver.Resolve(resolver.KindScanProfile, profile)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving scan profile")
+			return
+		}
+
+		members, err := target.FetchGroupTargetIDs(id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting targets of group")
+			return
+		}
+		if len(members) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group %s has no targets", id), "Error")
+			return
+		}
+
+		results := make(map[string]interface{})
+		for _, targetID := range members {
+			statusCode, responseBody := scan.StartScan(targetID, profile)
+			results[targetID] = map[string]interface{}{
+				"status_code": statusCode,
+				"response":    responseBody,
+			}
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+func init() {
+	ScanCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	ScanCmd.Flags().String("profile", "11111111-1111-1111-1111-111111111111", "Scan profile ID or name")
+	completion.Register(ScanCmd, resolver.KindGroup, "id")
+	completion.Register(ScanCmd, resolver.KindScanProfile, "profile")
+	ScanCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// scanCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// scanCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
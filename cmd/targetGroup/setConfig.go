@@ -0,0 +1,80 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/cmd/target"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// setConfigCmd represents the setConfig command
+var SetConfigCmd = &cobra.Command{
+	Use:   "setConfig",
+	Short: "Set the scan config of every target of a target group",
+	Long: `Sets the configuration fields given with --file and --set on every target in the target group.
+The rest of each target's configuration is kept. Example:
+	acucli targetGroup setConfig --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 -f cfg.yaml : Sets the fields declared in cfg.yaml
+	acucli targetGroup setConfig --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 --set scan_speed=slow
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+		file, _ := cmd.Flags().GetString("file")
+		sets, _ := cmd.Flags().GetStringArray("set")
+		if file == "" && len(sets) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("--file or --set is required"), "Error")
+			return
+		}
+
+		members, err := target.FetchGroupTargetIDs(id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting targets of group")
+			return
+		}
+		if len(members) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group %s has no targets", id), "Error")
+			return
+		}
+
+		results, err := target.ConfigureTargets(members, file, sets)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading configuration")
+			return
+		}
+
+		// Output only the JSON response
+		jsonoutput.OutputJSON(results)
+	},
+}
+
+func init() {
+	SetConfigCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(SetConfigCmd, resolver.KindGroup, "id")
+	SetConfigCmd.Flags().StringP("file", "f", "", "YAML file with the configuration fields to set")
+	SetConfigCmd.Flags().StringArray("set", []string{}, "Configuration field to set as key=value, can be repeated (e.g. login.kind=none)")
+	SetConfigCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// setConfigCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// setConfigCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
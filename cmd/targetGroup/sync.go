@@ -0,0 +1,104 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package targetGroup
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/cmd/target"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// syncCmd represents the sync command
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Set the members of a target group",
+	Long: `Takes the desired target IDs of the group from stdin, adds the missing targets and removes the others. Example:
+	cat production.txt | acucli targetGroup sync --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 --dry-run : Shows the targets that would be added and removed
+	cat production.txt | acucli targetGroup sync --id 0637a8b0-900d-44e8-9a04-edef6ac25e23 : Makes the group contain exactly the targets in the file
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ = cmd.Flags().GetString("id")
+		if id == "" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
+			return
+		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		// An empty input is refused so a broken pipe cannot empty the group
+		input, err := resolver.ReadStdin(resolver.KindTarget)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving targets")
+			return
+		}
+		if input == nil || len(input) == 0 {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("no target IDs provided"), "Error")
+			return
+		}
+
+		current, err := target.FetchGroupTargetIDs(id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error getting targets of group")
+			return
+		}
+
+		pBody := postBody{Add: missingIDs(input, current), Remove: missingIDs(current, input)}
+
+		if dryRun || (len(pBody.Add) == 0 && len(pBody.Remove) == 0) {
+			jsonoutput.OutputJSON(map[string]interface{}{
+				"dry_run":  dryRun,
+				"group_id": id,
+				"add":      pBody.Add,
+				"remove":   pBody.Remove,
+			})
+			return
+		}
+
+		updateGroupTargets(pBody, id)
+	},
+}
+
+// IDs of a which are not in b, sorted and de-duplicated
+func missingIDs(a, b []string) []string {
+	inB := make(map[string]bool)
+	for _, value := range b {
+		inB[value] = true
+	}
+
+	result := []string{}
+	for _, value := range a {
+		if !inB[value] {
+			result = append(result, value)
+			inB[value] = true
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func init() {
+	SyncCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(SyncCmd, resolver.KindGroup, "id")
+	SyncCmd.Flags().Bool("dry-run", false, "Show the changes without applying them")
+	SyncCmd.MarkFlagRequired("id")
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// syncCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// syncCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -11,8 +11,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/completion"
 	"github.com/tosbaa/acucli/helpers/httpclient"
 	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
 )
 
 type idResponseBody struct {
@@ -34,6 +36,12 @@ var TargetGroupCmd = &cobra.Command{
 			jsonoutput.OutputErrorAsJSON(fmt.Errorf("target group ID is required"), "Error")
 			return
 		}
+		groupID, err := resolver.Resolve(resolver.KindGroup, id)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error resolving target group")
+			return
+		}
+		id = groupID
 		GetTargetGroupRequest(id)
 	},
 }
@@ -72,13 +80,20 @@ func GetTargetGroupRequest(id string) {
 }
 
 func init() {
-	TargetGroupCmd.Flags().StringVarP(&id, "id", "", "", "Target ID")
+	TargetGroupCmd.Flags().StringVarP(&id, "id", "", "", "Target group ID or name")
+	completion.Register(TargetGroupCmd, resolver.KindGroup, "id")
 	TargetGroupCmd.MarkFlagRequired("id")
 
 	TargetGroupCmd.AddCommand(RemoveCmd)
 	TargetGroupCmd.AddCommand(AddCmd)
 	TargetGroupCmd.AddCommand(ListCmd)
 	TargetGroupCmd.AddCommand(AddTargetsCmd)
+	TargetGroupCmd.AddCommand(RemoveTargetsCmd)
+	TargetGroupCmd.AddCommand(RenameCmd)
+	TargetGroupCmd.AddCommand(SyncCmd)
+	TargetGroupCmd.AddCommand(ScanCmd)
+	TargetGroupCmd.AddCommand(ReportCmd)
+	TargetGroupCmd.AddCommand(SetConfigCmd)
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -0,0 +1,85 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tosbaa/acucli/cmd/report"
+	"github.com/tosbaa/acucli/cmd/scan"
+	"github.com/tosbaa/acucli/cmd/target"
+	"github.com/tosbaa/acucli/cmd/targetGroup"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// The commands print their responses, the interface needs them quietly: fail on status codes
+// other than the expected ones
+func expectStatus(statusCode int, body string, expected ...int) error {
+	for _, code := range expected {
+		if statusCode == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("status code %d: %s", statusCode, strings.TrimSpace(body))
+}
+
+// Start a scan of a target with the scan profile given by ID or name
+func startScan(targetID string, profile string) error {
+	profileID, err := resolver.Resolve(resolver.KindScanProfile, profile)
+	if err != nil {
+		return err
+	}
+	statusCode, body := scan.StartScan(targetID, profileID)
+	return expectStatus(statusCode, body, http.StatusCreated, http.StatusOK)
+}
+
+// Abort a running scan
+func abortScan(scanID string) error {
+	statusCode, body := scan.AbortScan(scanID)
+	return expectStatus(statusCode, body, http.StatusNoContent, http.StatusOK)
+}
+
+// Generate a report of a scan with the template given by ID or name
+func generateReport(scanID string, template string) error {
+	templateID, err := report.ResolveTemplateID(template)
+	if err != nil {
+		return err
+	}
+	statusCode, body, err := report.RequestReport(templateID, "Report generated by acucli", "scans", []string{scanID})
+	if err != nil {
+		return err
+	}
+	return expectStatus(statusCode, body, http.StatusCreated, http.StatusOK)
+}
+
+func deleteTarget(targetID string) error {
+	statusCode, body, err := target.DeleteTargets([]string{targetID})
+	if err != nil {
+		return err
+	}
+	return expectStatus(statusCode, body, http.StatusNoContent, http.StatusOK)
+}
+
+func deleteGroup(groupID string) error {
+	statusCode, body, err := targetGroup.DeleteTargetGroups([]string{groupID})
+	if err != nil {
+		return err
+	}
+	return expectStatus(statusCode, body, http.StatusNoContent, http.StatusOK)
+}
+
+func deleteScan(scanID string) error {
+	statusCode, body := scan.RemoveScan(scanID)
+	return expectStatus(statusCode, body, http.StatusNoContent, http.StatusOK)
+}
+
+func deleteReport(reportID string) error {
+	statusCode, body, err := report.RemoveReports([]string{reportID})
+	if err != nil {
+		return err
+	}
+	return expectStatus(statusCode, body, http.StatusNoContent, http.StatusOK)
+}
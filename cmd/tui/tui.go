@@ -0,0 +1,419 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// place is a view with its scope, remembered to go back to it
+type place struct {
+	view     string
+	scope    string
+	label    string
+	selected int
+}
+
+// app is the state of the interface. Everything except the requests runs in the event loop of tview.
+type app struct {
+	tv       *tview.Application
+	pages    *tview.Pages
+	tabBar   *tview.TextView
+	grid     *tview.Table
+	status   *tview.TextView
+	profile  string
+	template string
+
+	current place
+	history []place
+	content table
+	// notice is shown in the status line once the view is reloaded after an action
+	notice string
+}
+
+func newApp(profile string, template string) *app {
+	a := &app{
+		tv:       tview.NewApplication(),
+		pages:    tview.NewPages(),
+		tabBar:   tview.NewTextView().SetDynamicColors(true),
+		grid:     tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		status:   tview.NewTextView().SetDynamicColors(true),
+		profile:  profile,
+		template: template,
+		current:  place{view: viewScans},
+	}
+	a.grid.SetBorder(true)
+	a.grid.SetSelectedFunc(func(r, c int) { a.open(r - 1) })
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.tabBar, 1, 0, false).
+		AddItem(a.grid, 0, 1, true).
+		AddItem(a.status, 1, 0, false)
+	a.pages.AddPage("main", layout, true, true)
+
+	a.tv.SetInputCapture(a.handleKey)
+	a.tv.SetRoot(a.pages, true).SetFocus(a.grid)
+	return a
+}
+
+// Global keys, only handled while no dialog is open
+func (a *app) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if name, _ := a.pages.GetFrontPage(); name != "main" {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+		a.back()
+		return nil
+	case tcell.KeyF5, tcell.KeyCtrlR:
+		a.reload(false)
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch r := event.Rune(); r {
+	case 'q':
+		a.tv.Stop()
+	case '1', '2', '3', '4':
+		a.history = nil
+		a.show(place{view: tabs[r-'1']})
+	case 's':
+		a.startScan()
+	case 'a':
+		a.abortScan()
+	case 'r':
+		a.generateReport()
+	case 'd':
+		a.delete()
+	default:
+		return event
+	}
+	return nil
+}
+
+// Show a view and load it
+func (a *app) show(p place) {
+	a.current = p
+	a.content = table{}
+	a.draw()
+	a.reload(false)
+}
+
+// Go back to the previous view
+func (a *app) back() {
+	if len(a.history) == 0 {
+		return
+	}
+	previous := a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+	a.show(previous)
+}
+
+// Open the selected row: the targets of a group, the scans of a target, the vulnerabilities of a scan,
+// the details of a vulnerability or the downloads of a report
+func (a *app) open(index int) {
+	selected, ok := a.row(index)
+	if !ok {
+		return
+	}
+
+	var next place
+	switch a.current.view {
+	case viewGroups:
+		next = place{view: viewTargets, scope: selected.id, label: "group " + selected.ref}
+	case viewTargets:
+		next = place{view: viewScans, scope: selected.id, label: selected.ref}
+	case viewScans:
+		next = place{view: viewVulnerabilities, scope: selected.ref, label: selected.cells[0]}
+	case viewVulnerabilities:
+		scanID, resultID, _ := strings.Cut(a.current.scope, "/")
+		a.setStatus("Loading details...")
+		go func() {
+			text, err := vulnerabilityDetails(scanID, resultID, selected.id)
+			a.tv.QueueUpdateDraw(func() {
+				if err != nil {
+					a.setError(err)
+					return
+				}
+				a.setStatus("")
+				a.showText(selected.cells[1], text)
+			})
+		}()
+		return
+	case viewReports:
+		text := selected.ref
+		if text == "" {
+			text = "The report has no downloads yet."
+		}
+		a.showText("Downloads", text)
+		return
+	default:
+		return
+	}
+
+	a.current.selected = index
+	a.history = append(a.history, a.current)
+	a.show(next)
+}
+
+// Load the current view in the background, the selection is kept on the same object.
+// Quiet reloads, the periodic refreshes, leave the status line alone.
+func (a *app) reload(quiet bool) {
+	p := a.current
+	if !quiet {
+		a.setStatus("Loading...")
+	}
+	go func() {
+		content, err := load(p.view, p.scope)
+		a.tv.QueueUpdateDraw(func() {
+			if a.current.view != p.view || a.current.scope != p.scope {
+				return
+			}
+			if err != nil {
+				a.setError(err)
+				return
+			}
+			selectedID := ""
+			if selected, ok := a.row(a.selectedIndex()); ok {
+				selectedID = selected.id
+			}
+			a.content = content
+			a.draw()
+			a.selectRow(selectedID)
+			if !quiet {
+				a.setStatus(a.notice)
+				a.notice = ""
+			}
+		})
+	}()
+}
+
+// Draw the tabs and the table of the current view
+func (a *app) draw() {
+	var tabText []string
+	for i, tab := range tabs {
+		if tab == a.current.view || (a.current.view == viewVulnerabilities && tab == viewScans) {
+			tabText = append(tabText, fmt.Sprintf("[black:white] %d %s [-:-]", i+1, tab))
+		} else {
+			tabText = append(tabText, fmt.Sprintf(" %d %s ", i+1, tab))
+		}
+	}
+	a.tabBar.SetText(strings.Join(tabText, " "))
+
+	title := " " + a.current.view + " "
+	if a.current.label != "" {
+		title = fmt.Sprintf(" %s of %s ", a.current.view, a.current.label)
+	}
+	a.grid.SetTitle(tview.Escape(title))
+
+	selected := a.current.selected
+	a.grid.Clear()
+	for c, header := range a.content.headers {
+		a.grid.SetCell(0, c, tview.NewTableCell(header).SetTextColor(tcell.ColorAqua).SetSelectable(false).SetExpansion(1))
+	}
+	for r, line := range a.content.rows {
+		for c, text := range line.cells {
+			a.grid.SetCell(r+1, c, tview.NewTableCell(tview.Escape(text)).SetTextColor(line.color).SetMaxWidth(60).SetExpansion(1))
+		}
+	}
+	if selected >= len(a.content.rows) {
+		selected = len(a.content.rows) - 1
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	a.grid.Select(selected+1, 0)
+}
+
+func (a *app) selectedIndex() int {
+	r, _ := a.grid.GetSelection()
+	return r - 1
+}
+
+func (a *app) row(index int) (row, bool) {
+	if index < 0 || index >= len(a.content.rows) {
+		return row{}, false
+	}
+	return a.content.rows[index], true
+}
+
+func (a *app) selectRow(id string) {
+	for i, line := range a.content.rows {
+		if line.id == id {
+			a.grid.Select(i+1, 0)
+			return
+		}
+	}
+}
+
+func (a *app) setStatus(message string) {
+	if message == "" {
+		message = "[gray]" + viewKeys[a.current.view] + "  F5 refresh  esc back  1-4 tabs  q quit"
+	}
+	a.status.SetText(message)
+}
+
+func (a *app) setError(err error) {
+	a.status.SetText("[red]" + tview.Escape(err.Error()))
+}
+
+// Show a scrollable text, escape closes it
+func (a *app) showText(title string, text string) {
+	view := tview.NewTextView().SetText(text).SetScrollable(true).SetWordWrap(true)
+	view.SetBorder(true).SetTitle(" " + tview.Escape(title) + " (esc to close) ")
+	view.SetDoneFunc(func(key tcell.Key) {
+		a.pages.RemovePage("text")
+		a.tv.SetFocus(a.grid)
+	})
+	a.pages.AddPage("text", view, true, true)
+	a.tv.SetFocus(view)
+}
+
+// Ask before an action, the action runs in the background when confirmed
+func (a *app) confirm(question string, button string, action func() error, done string) {
+	modal := tview.NewModal().SetText(question).AddButtons([]string{button, "Cancel"})
+	modal.SetDoneFunc(func(index int, label string) {
+		a.pages.RemovePage("confirm")
+		a.tv.SetFocus(a.grid)
+		if label == button {
+			a.run(action, done)
+		}
+	})
+	a.pages.AddPage("confirm", modal, false, true)
+	a.tv.SetFocus(modal)
+}
+
+// Run an action in the background, then show its outcome and reload the view
+func (a *app) run(action func() error, done string) {
+	a.setStatus("Working...")
+	go func() {
+		err := action()
+		a.tv.QueueUpdateDraw(func() {
+			if err != nil {
+				a.setError(err)
+				return
+			}
+			a.notice = "[green]" + tview.Escape(done)
+			a.reload(false)
+		})
+	}()
+}
+
+func (a *app) startScan() {
+	selected, ok := a.row(a.selectedIndex())
+	if !ok || a.current.view != viewTargets {
+		return
+	}
+	a.run(func() error { return startScan(selected.id, a.profile) }, "Scan of "+selected.ref+" started")
+}
+
+func (a *app) abortScan() {
+	selected, ok := a.row(a.selectedIndex())
+	if !ok || a.current.view != viewScans {
+		return
+	}
+	a.confirm("Abort the scan of "+selected.cells[0]+"?", "Abort", func() error { return abortScan(selected.id) }, "Scan aborted")
+}
+
+func (a *app) generateReport() {
+	selected, ok := a.row(a.selectedIndex())
+	if !ok || a.current.view != viewScans {
+		return
+	}
+	a.run(func() error { return generateReport(selected.id, a.template) }, "Report of "+selected.cells[0]+" requested, see the reports tab")
+}
+
+func (a *app) delete() {
+	selected, ok := a.row(a.selectedIndex())
+	if !ok {
+		return
+	}
+	switch a.current.view {
+	case viewTargets:
+		a.confirm("Delete the target "+selected.ref+" and its scans?", "Delete", func() error { return deleteTarget(selected.id) }, "Target deleted")
+	case viewGroups:
+		a.confirm("Delete the group "+selected.ref+"? Its targets are kept.", "Delete", func() error { return deleteGroup(selected.id) }, "Group deleted")
+	case viewScans:
+		a.confirm("Delete the scan of "+selected.cells[0]+"?", "Delete", func() error { return deleteScan(selected.id) }, "Scan deleted")
+	case viewReports:
+		a.confirm("Delete the report "+selected.cells[0]+"?", "Delete", func() error { return deleteReport(selected.id) }, "Report deleted")
+	}
+}
+
+// tuiCmd represents the tui command
+var TuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen terminal interface for targets, groups, scans and reports",
+	Long: `Opens a full-screen terminal interface listing targets, target groups, scans and reports. Scans and
+reports are refreshed every --interval seconds. Keys:
+
+1-4      targets, groups, scans, reports
+enter    open: the targets of a group, the scans of a target, the vulnerabilities of a scan, vulnerability details
+esc      back
+s        start a scan of the selected target (--scanProfileID)
+a        abort the selected scan
+r        generate a report of the selected scan (--reportTemplateID)
+d        delete the selected target, group, scan or report
+F5       refresh
+q        quit
+
+Example:
+
+acucli tui
+acucli tui --scanProfileID "High Risk Vulnerabilities" --interval 10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("scanProfileID")
+		template, _ := cmd.Flags().GetString("reportTemplateID")
+		interval, _ := cmd.Flags().GetInt("interval")
+		if interval < 1 {
+			interval = 1
+		}
+
+		a := newApp(profile, template)
+		a.setStatus("")
+		a.show(a.current)
+
+		// Scan progress and report status change on their own
+		go func() {
+			for range time.Tick(time.Duration(interval) * time.Second) {
+				a.tv.QueueUpdate(func() {
+					if name, _ := a.pages.GetFrontPage(); name == "main" && (a.current.view == viewScans || a.current.view == viewReports) {
+						a.reload(true)
+					}
+				})
+			}
+		}()
+
+		return a.tv.Run()
+	},
+}
+
+func init() {
+	TuiCmd.Flags().String("scanProfileID", "11111111-1111-1111-1111-111111111111", "Scan profile ID or name used to start scans")
+	TuiCmd.Flags().String("reportTemplateID", "11111111-1111-1111-1111-111111111126", "Report template ID or name used to generate reports")
+	TuiCmd.Flags().Int("interval", 5, "Seconds between refreshes of the scans and reports")
+	completion.Register(TuiCmd, resolver.KindScanProfile, "scanProfileID")
+	completion.Register(TuiCmd, resolver.KindReportTemplate, "reportTemplateID")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// tuiCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// tuiCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Views of the interface, the first four are the tabs
+const (
+	viewTargets         = "targets"
+	viewGroups          = "groups"
+	viewScans           = "scans"
+	viewReports         = "reports"
+	viewVulnerabilities = "vulnerabilities"
+)
+
+var tabs = []string{viewTargets, viewGroups, viewScans, viewReports}
+
+// Keys of the actions of each view, shown in the status line
+var viewKeys = map[string]string{
+	viewTargets:         "enter scans  s start scan  d delete",
+	viewGroups:          "enter targets  d delete",
+	viewScans:           "enter vulnerabilities  a abort  r report  d delete",
+	viewReports:         "enter downloads  d delete",
+	viewVulnerabilities: "enter details",
+}
+
+// row is a line of a table, id is the object the actions apply to and ref holds what a drill-down needs
+type row struct {
+	id    string
+	ref   string
+	cells []string
+	color tcell.Color
+}
+
+// table is the loaded content of a view
+type table struct {
+	headers []string
+	rows    []row
+}
+
+// Load a view. The scope narrows it: the group of the targets view, the target of the scans view
+// and "<scan ID>/<result ID>" of the vulnerabilities view.
+func load(view string, scope string) (table, error) {
+	switch view {
+	case viewTargets:
+		return loadTargets(scope)
+	case viewGroups:
+		return loadGroups()
+	case viewScans:
+		return loadScans(scope)
+	case viewReports:
+		return loadReports()
+	case viewVulnerabilities:
+		scanID, resultID, _ := strings.Cut(scope, "/")
+		return loadVulnerabilities(scanID, resultID)
+	}
+	return table{}, fmt.Errorf("unknown view %s", view)
+}
+
+func loadTargets(groupID string) (table, error) {
+	targets, err := scandata.FetchTargets()
+	if err != nil {
+		return table{}, err
+	}
+	if groupID != "" {
+		ids, err := scandata.FetchGroupTargets(groupID)
+		if err != nil {
+			return table{}, err
+		}
+		members := make(map[string]bool)
+		for _, id := range ids {
+			members[id] = true
+		}
+		var filtered []scandata.Target
+		for _, target := range targets {
+			if members[target.TargetID] {
+				filtered = append(filtered, target)
+			}
+		}
+		targets = filtered
+	}
+	sort.Slice(targets, func(i, k int) bool { return targets[i].Address < targets[k].Address })
+
+	t := table{headers: []string{"Address", "Description", "Criticality"}}
+	for _, target := range targets {
+		t.rows = append(t.rows, row{
+			id:    target.TargetID,
+			ref:   target.Address,
+			cells: []string{target.Address, target.Description, fmt.Sprint(target.Criticality)},
+			color: tcell.ColorWhite,
+		})
+	}
+	return t, nil
+}
+
+func loadGroups() (table, error) {
+	groups, err := scandata.FetchTargetGroups()
+	if err != nil {
+		return table{}, err
+	}
+	sort.Slice(groups, func(i, k int) bool { return strings.ToLower(groups[i].Name) < strings.ToLower(groups[k].Name) })
+
+	t := table{headers: []string{"Name", "Targets", "Description"}}
+	for _, group := range groups {
+		t.rows = append(t.rows, row{
+			id:    group.GroupID,
+			ref:   group.Name,
+			cells: []string{group.Name, fmt.Sprint(group.TargetCount), group.Description},
+			color: tcell.ColorWhite,
+		})
+	}
+	return t, nil
+}
+
+func loadScans(targetID string) (table, error) {
+	scans, err := scandata.FetchScans()
+	if err != nil {
+		return table{}, err
+	}
+	sort.Slice(scans, func(i, k int) bool { return scans[i].CurrentSession.StartDate > scans[k].CurrentSession.StartDate })
+
+	t := table{headers: []string{"Target", "Profile", "Status", "Progress", "Started", "Crit", "High", "Med", "Low", "Info"}}
+	for _, scan := range scans {
+		if targetID != "" && scan.TargetID != targetID {
+			continue
+		}
+		session := scan.CurrentSession
+		counts := session.SeverityCounts
+		t.rows = append(t.rows, row{
+			id:  scan.ScanID,
+			ref: scan.ScanID + "/" + session.ScanSessionID,
+			cells: []string{
+				scan.Target.Address, scan.ProfileName, session.Status, fmt.Sprintf("%3d%%", session.Progress), shortDate(session.StartDate),
+				fmt.Sprint(counts["critical"]), fmt.Sprint(counts["high"]), fmt.Sprint(counts["medium"]), fmt.Sprint(counts["low"]), fmt.Sprint(counts["info"]),
+			},
+			color: statusColor(session.Status),
+		})
+	}
+	return t, nil
+}
+
+func loadReports() (table, error) {
+	reports, err := scandata.FetchReports()
+	if err != nil {
+		return table{}, err
+	}
+	sort.Slice(reports, func(i, k int) bool { return reports[i].GenerationDate > reports[k].GenerationDate })
+
+	t := table{headers: []string{"Template", "Description", "Status", "Generated"}}
+	for _, report := range reports {
+		t.rows = append(t.rows, row{
+			id:    report.ReportID,
+			ref:   strings.Join(report.Download, "\n"),
+			cells: []string{report.TemplateName, report.Source.Description, report.Status, shortDate(report.GenerationDate)},
+			color: statusColor(report.Status),
+		})
+	}
+	return t, nil
+}
+
+func loadVulnerabilities(scanID string, resultID string) (table, error) {
+	if resultID == "" {
+		return table{}, fmt.Errorf("the scan has no result yet")
+	}
+	vulnerabilities, err := scandata.FetchVulnerabilities(scanID, resultID)
+	if err != nil {
+		return table{}, err
+	}
+	scandata.SortVulnerabilities(vulnerabilities)
+
+	t := table{headers: []string{"Severity", "Name", "URL", "Parameter", "Status"}}
+	for _, v := range vulnerabilities {
+		t.rows = append(t.rows, row{
+			id:    v.VulnID,
+			cells: []string{v.SeverityName(), v.Name, v.AffectsURL, v.AffectsDetail, v.Status},
+			color: severityColor(v.Severity),
+		})
+	}
+	return t, nil
+}
+
+// The details of a vulnerability as text
+func vulnerabilityDetails(scanID string, resultID string, vulnID string) (string, error) {
+	v, err := scandata.FetchVulnerabilityDetails(scanID, resultID, vulnID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", v.Name)
+	fmt.Fprintf(&b, "Severity:   %s\n", scandata.SeverityName(v.Severity))
+	fmt.Fprintf(&b, "URL:        %s\n", v.AffectsURL)
+	if v.AffectsDetail != "" {
+		fmt.Fprintf(&b, "Parameter:  %s\n", v.AffectsDetail)
+	}
+	sections := [][2]string{
+		{"Description", v.Description},
+		{"Details", v.Details},
+		{"Impact", v.Impact},
+		{"Recommendation", v.Recommendation},
+		{"Request", v.Request},
+	}
+	for _, section := range sections {
+		if text := strings.TrimSpace(scandata.PlainText(section[1])); text != "" {
+			fmt.Fprintf(&b, "\n%s\n%s\n", section[0], text)
+		}
+	}
+	if len(v.References) > 0 {
+		b.WriteString("\nReferences\n")
+		for _, reference := range v.References {
+			fmt.Fprintf(&b, "%s: %s\n", reference.Rel, reference.Href)
+		}
+	}
+	return b.String(), nil
+}
+
+func shortDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func statusColor(status string) tcell.Color {
+	switch status {
+	case "processing", "starting", "queued", "aborting", "pausing":
+		return tcell.ColorYellow
+	case "completed":
+		return tcell.ColorGreen
+	case "failed", "aborted":
+		return tcell.ColorRed
+	}
+	return tcell.ColorWhite
+}
+
+func severityColor(severity int) tcell.Color {
+	switch severity {
+	case scandata.SeverityCritical:
+		return tcell.ColorRed
+	case scandata.SeverityHigh:
+		return tcell.ColorOrange
+	case scandata.SeverityMedium:
+		return tcell.ColorYellow
+	case scandata.SeverityLow:
+		return tcell.ColorLightBlue
+	}
+	return tcell.ColorGray
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package vulnerability
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/vulnformat"
+)
+
+// listCmd represents the list command
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the vulnerabilities of all targets",
+	Long: `Lists the vulnerabilities of all targets matching a query (by default the open ones). With --format defectdojo the
+Acunetix XML format ("Acunetix Scan" in DefectDojo) is written, with --format generic-findings the DefectDojo Generic
+Findings Import JSON. Example:
+
+acucli vulnerability list
+acucli vulnerability list --target https://shop.example.com --query "status:open;severity:3,4"
+acucli vulnerability list --format generic-findings > findings.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targetFlag, _ := cmd.Flags().GetString("target")
+		query, _ := cmd.Flags().GetString("query")
+		format, _ := cmd.Flags().GetString("format")
+
+		if !vulnformat.Valid(format) {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid format %q (%s)", format, strings.Join(vulnformat.Formats, ", ")), "Error")
+			return
+		}
+		if targetFlag != "" {
+			targetID, err := resolver.Resolve(resolver.KindTarget, targetFlag)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving target")
+				return
+			}
+			query = strings.Trim(query+";target_id:"+targetID, ";")
+		}
+
+		vulnerabilities, err := scandata.FetchAllVulnerabilities(query)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing vulnerabilities")
+			return
+		}
+
+		if format == vulnformat.FormatJSON {
+			jsonoutput.OutputJSON(map[string]interface{}{"vulnerabilities": vulnerabilities})
+			return
+		}
+
+		for i, v := range vulnerabilities {
+			detailed, err := scandata.FetchVulnerability(v.VulnID)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error getting vulnerability "+v.VulnID)
+				return
+			}
+			vulnerabilities[i] = v.WithDetails(detailed)
+		}
+		scandata.SortVulnerabilities(vulnerabilities)
+
+		addresses := make(map[string]string)
+		if targets, err := resolver.List(resolver.KindTarget); err == nil {
+			for _, target := range targets {
+				addresses[target.ID] = target.Name
+			}
+		}
+
+		data, err := vulnformat.Encode(format, vulnformat.GroupByTarget(vulnerabilities, addresses))
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error encoding vulnerabilities")
+			return
+		}
+		os.Stdout.Write(data)
+	},
+}
+
+func init() {
+	ListCmd.Flags().String("target", "", "Only the vulnerabilities of this target (ID or address)")
+	completion.Register(ListCmd, resolver.KindTarget, "target")
+	ListCmd.Flags().String("query", "status:open", "Vulnerability filter of the server, e.g. status:open;severity:3,4")
+	ListCmd.Flags().String("format", "json", "Output format: json, defectdojo or generic-findings")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// listCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
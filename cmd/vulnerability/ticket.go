@@ -0,0 +1,498 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package vulnerability
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tosbaa/acucli/helpers/completion"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/jsonoutput"
+	"github.com/tosbaa/acucli/helpers/resolver"
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/store"
+)
+
+// Number of locations whose evidence is fetched and embedded in a ticket
+const maxEvidence = 10
+
+// Request headers whose values are redacted in the evidence, with the custom headers configured on the target
+var redactedHeaders = []string{"Cookie", "Authorization", "Proxy-Authorization"}
+
+// ticketRecord maps a ticket to the fingerprints of the findings it was created for
+type ticketRecord struct {
+	Tracker      string     `json:"tracker"`
+	Key          string     `json:"key"`
+	URL          string     `json:"url"`
+	Title        string     `json:"title"`
+	TargetID     string     `json:"target_id"`
+	VtID         string     `json:"vt_id"`
+	Fingerprints []string   `json:"fingerprints"`
+	Open         bool       `json:"open"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+}
+
+// location is a place a vulnerability was found at, with its evidence
+type location struct {
+	URL     string
+	Detail  string
+	Details string
+	Request string
+}
+
+// ticket is the content of a ticket for one finding, or for every finding of a vulnerability type on a target
+type ticket struct {
+	Title           string
+	Name            string
+	Severity        string
+	Address         string
+	Description     string
+	Impact          string
+	Recommendation  string
+	References      []scandata.Reference
+	Locations       []location
+	Fingerprints    []string
+	TargetID        string
+	VtID            string
+	vulnerabilities []scandata.Vulnerability
+}
+
+// TicketCmd represents the ticket command
+var TicketCmd = &cobra.Command{
+	Use:   "ticket",
+	Short: "Create issue tracker tickets for open vulnerabilities",
+	Long: `Creates a Jira or GitHub issue for every open vulnerability (or, with --group-by vt, for every vulnerability type
+of a target) with its evidence and remediation. The tickets created are remembered in the local database by finding
+fingerprint, so running the command again only creates tickets for new findings. New locations of a vulnerability type
+which already has an open ticket are added to that ticket as a comment. With --close-fixed, tickets whose
+findings are no longer open are commented on and closed. The values of the Cookie, Authorization and
+Proxy-Authorization headers and of the target's custom headers are redacted from the requests in the evidence.
+The trackers are configured under "trackers" in the config file. Example:
+
+acucli vulnerability ticket --tracker jira --min-severity high
+acucli vulnerability ticket --tracker github --target https://shop.example.com --group-by vt --close-fixed
+acucli vulnerability ticket --tracker github --dry-run : Shows the tickets which would be created or closed`,
+	Run: func(cmd *cobra.Command, args []string) {
+		trackerName, _ := cmd.Flags().GetString("tracker")
+		targetFlag, _ := cmd.Flags().GetString("target")
+		minSeverity, _ := cmd.Flags().GetString("min-severity")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		closeFixed, _ := cmd.Flags().GetBool("close-fixed")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		tracker, err := newTracker(trackerName)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+		severity, ok := scandata.SeverityValue(strings.ToLower(minSeverity))
+		if !ok {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid severity %q (critical, high, medium, low, info)", minSeverity), "Error")
+			return
+		}
+		if groupBy != "finding" && groupBy != "vt" {
+			jsonoutput.OutputErrorAsJSON(fmt.Errorf("invalid --group-by %q (finding, vt)", groupBy), "Error")
+			return
+		}
+
+		query := "status:open"
+		targetID := ""
+		if targetFlag != "" {
+			targetID, err = resolver.Resolve(resolver.KindTarget, targetFlag)
+			if err != nil {
+				jsonoutput.OutputErrorAsJSON(err, "Error resolving target")
+				return
+			}
+			query += ";target_id:" + targetID
+		}
+
+		open, err := scandata.FetchAllVulnerabilities(query)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error listing vulnerabilities")
+			return
+		}
+
+		db, err := store.Open()
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error")
+			return
+		}
+		defer db.Close()
+
+		records, err := loadTicketRecords(db, trackerName)
+		if err != nil {
+			jsonoutput.OutputErrorAsJSON(err, "Error reading tickets")
+			return
+		}
+
+		result := map[string]interface{}{"tracker": trackerName, "dry_run": dryRun}
+		var errors []string
+
+		tickets := buildTickets(open, severity, groupBy, records)
+		created := []map[string]interface{}{}
+		updated := []map[string]interface{}{}
+		sensitiveByTarget := make(map[string]map[string]bool)
+		for _, t := range tickets {
+			entry := map[string]interface{}{"title": t.Title, "findings": len(t.Fingerprints)}
+
+			// A vulnerability type found at new locations goes to its open ticket
+			recordKey := ticketKey(trackerName, groupBy, t)
+			if record, ok := records[recordKey]; ok && record.Open {
+				entry["key"] = record.Key
+				entry["url"] = record.URL
+				if dryRun {
+					updated = append(updated, entry)
+					continue
+				}
+				if err := tracker.Comment(record.Key, t.newLocationsComment()); err != nil {
+					errors = append(errors, fmt.Sprintf("%s: %v", record.Key, err))
+					continue
+				}
+				record.Fingerprints = append(record.Fingerprints, t.Fingerprints...)
+				records[recordKey] = record
+				if err := db.Put(store.BucketTickets, recordKey, record); err != nil {
+					errors = append(errors, fmt.Sprintf("%s: commented but the mapping was not stored: %v", record.Key, err))
+				}
+				updated = append(updated, entry)
+				continue
+			}
+
+			if dryRun {
+				created = append(created, entry)
+				continue
+			}
+
+			sensitive, ok := sensitiveByTarget[t.TargetID]
+			if !ok {
+				sensitive = sensitiveHeaders(t.TargetID)
+				sensitiveByTarget[t.TargetID] = sensitive
+			}
+			addEvidence(&t, sensitive)
+			key, url, err := tracker.Create(t)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", t.Title, err))
+				continue
+			}
+			record := ticketRecord{
+				Tracker:      trackerName,
+				Key:          key,
+				URL:          url,
+				Title:        t.Title,
+				TargetID:     t.TargetID,
+				VtID:         t.VtID,
+				Fingerprints: t.Fingerprints,
+				Open:         true,
+				CreatedAt:    time.Now(),
+			}
+			records[recordKey] = record
+			if err := db.Put(store.BucketTickets, recordKey, record); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: created %s but the mapping was not stored: %v", t.Title, key, err))
+			}
+			entry["key"] = key
+			entry["url"] = url
+			created = append(created, entry)
+		}
+		result["created"] = created
+		if groupBy == "vt" {
+			result["updated"] = updated
+		}
+
+		if closeFixed {
+			closed := []map[string]interface{}{}
+			for _, key := range fixedTickets(records, open, targetID) {
+				record := records[key]
+				entry := map[string]interface{}{"key": record.Key, "url": record.URL, "title": record.Title}
+				if dryRun {
+					closed = append(closed, entry)
+					continue
+				}
+				if err := tracker.Close(record.Key, "Fixed: Acunetix no longer reports the findings of this ticket as open."); err != nil {
+					errors = append(errors, fmt.Sprintf("%s: %v", record.Key, err))
+					continue
+				}
+				now := time.Now()
+				record.Open = false
+				record.ClosedAt = &now
+				if err := db.Put(store.BucketTickets, key, record); err != nil {
+					errors = append(errors, fmt.Sprintf("%s: closed but the mapping was not stored: %v", record.Key, err))
+				}
+				closed = append(closed, entry)
+			}
+			result["closed"] = closed
+		}
+
+		if len(errors) > 0 {
+			result["errors"] = errors
+		}
+		jsonoutput.OutputJSON(result)
+	},
+}
+
+// Read the stored tickets of a tracker
+func loadTicketRecords(db *store.Store, trackerName string) (map[string]ticketRecord, error) {
+	records := make(map[string]ticketRecord)
+	err := db.ForEach(store.BucketTickets, trackerName+"/", func(key string, data []byte) error {
+		var record ticketRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		records[key] = record
+		return nil
+	})
+	return records, err
+}
+
+// Key of the stored ticket: per finding fingerprint, or per target and vulnerability type
+func ticketKey(trackerName string, groupBy string, t ticket) string {
+	if groupBy == "vt" {
+		return fmt.Sprintf("%s/vt/%s/%s", trackerName, t.TargetID, t.VtID)
+	}
+	return fmt.Sprintf("%s/%s", trackerName, t.Fingerprints[0])
+}
+
+// Group the findings at or above the severity into tickets, leaving out the findings which already have an open ticket
+func buildTickets(vulnerabilities []scandata.Vulnerability, severity int, groupBy string, records map[string]ticketRecord) []ticket {
+	covered := make(map[string]bool)
+	for _, record := range records {
+		if record.Open {
+			for _, fingerprint := range record.Fingerprints {
+				covered[fingerprint] = true
+			}
+		}
+	}
+
+	addresses := make(map[string]string)
+	if targets, err := resolver.List(resolver.KindTarget); err == nil {
+		for _, target := range targets {
+			addresses[target.ID] = target.Name
+		}
+	}
+
+	scandata.SortVulnerabilities(vulnerabilities)
+	var tickets []ticket
+	index := make(map[string]int)
+	for _, v := range vulnerabilities {
+		fingerprint := v.Fingerprint(v.TargetID)
+		if v.Severity < severity || covered[fingerprint] {
+			continue
+		}
+		covered[fingerprint] = true
+
+		group := fingerprint
+		if groupBy == "vt" {
+			group = v.TargetID + "/" + v.VtID
+		}
+		i, ok := index[group]
+		if !ok {
+			i = len(tickets)
+			index[group] = i
+			tickets = append(tickets, ticket{
+				Name:     v.Name,
+				Severity: v.SeverityName(),
+				Address:  addresses[v.TargetID],
+				TargetID: v.TargetID,
+				VtID:     v.VtID,
+			})
+		}
+		tickets[i].Fingerprints = append(tickets[i].Fingerprints, fingerprint)
+		tickets[i].vulnerabilities = append(tickets[i].vulnerabilities, v)
+	}
+
+	for i := range tickets {
+		t := &tickets[i]
+		if len(t.vulnerabilities) == 1 {
+			t.Title = fmt.Sprintf("[Acunetix][%s] %s at %s", strings.ToUpper(t.Severity), t.Name, t.vulnerabilities[0].AffectsURL)
+		} else {
+			t.Title = fmt.Sprintf("[Acunetix][%s] %s on %s (%d locations)", strings.ToUpper(t.Severity), t.Name, t.Address, len(t.vulnerabilities))
+		}
+	}
+	return tickets
+}
+
+// Fetch the description, remediation and evidence of the ticket's findings, redacting the sensitive headers
+// of the requests
+func addEvidence(t *ticket, sensitive map[string]bool) {
+	for i, v := range t.vulnerabilities {
+		loc := location{URL: v.AffectsURL, Detail: v.AffectsDetail}
+		if i < maxEvidence {
+			if detailed, err := scandata.FetchVulnerability(v.VulnID); err == nil {
+				loc.Details = scandata.PlainText(detailed.Details)
+				loc.Request = redactRequest(detailed.Request, sensitive)
+				if t.Description == "" {
+					t.Description = scandata.PlainText(detailed.Description)
+					t.Impact = scandata.PlainText(detailed.Impact)
+					t.Recommendation = scandata.PlainText(detailed.Recommendation)
+					t.References = detailed.References
+				}
+			}
+		}
+		t.Locations = append(t.Locations, loc)
+	}
+}
+
+// Lower case names of the headers to redact in the requests of a target: the credential headers and the custom
+// headers configured on the target, which carry the session set with target auth set --header
+func sensitiveHeaders(targetID string) map[string]bool {
+	sensitive := make(map[string]bool)
+	for _, name := range redactedHeaders {
+		sensitive[strings.ToLower(name)] = true
+	}
+
+	body, err := httpclient.Get(fmt.Sprintf("/targets/%s/configuration", targetID))
+	if err != nil {
+		return sensitive
+	}
+	var config struct {
+		CustomHeaders []string `json:"custom_headers"`
+	}
+	if json.Unmarshal(body, &config) != nil {
+		return sensitive
+	}
+	for _, header := range config.CustomHeaders {
+		if name, _, found := strings.Cut(header, ":"); found {
+			sensitive[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+	}
+	return sensitive
+}
+
+// Replace the values of the sensitive headers of a raw HTTP request
+func redactRequest(request string, sensitive map[string]bool) string {
+	lines := strings.Split(request, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+		if line == "" {
+			// End of the headers
+			break
+		}
+		name, _, found := strings.Cut(line, ":")
+		if found && sensitive[strings.ToLower(strings.TrimSpace(name))] {
+			lines[i] = name + ": [redacted]" + strings.TrimPrefix(lines[i], line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Open tickets in scope none of whose findings is open anymore
+func fixedTickets(records map[string]ticketRecord, open []scandata.Vulnerability, targetID string) []string {
+	stillOpen := make(map[string]bool)
+	for _, v := range open {
+		stillOpen[v.Fingerprint(v.TargetID)] = true
+	}
+
+	var keys []string
+	for key, record := range records {
+		if !record.Open || (targetID != "" && record.TargetID != targetID) {
+			continue
+		}
+		fixed := true
+		for _, fingerprint := range record.Fingerprints {
+			if stillOpen[fingerprint] {
+				fixed = false
+				break
+			}
+		}
+		if fixed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Comment listing the locations of a ticket which were added to an existing ticket
+func (t ticket) newLocationsComment() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("New locations of %s found by Acunetix:\n", t.Name))
+	for _, v := range t.vulnerabilities {
+		sb.WriteString("\n- " + v.AffectsURL)
+		if v.AffectsDetail != "" {
+			sb.WriteString(" (" + v.AffectsDetail + ")")
+		}
+	}
+	return sb.String()
+}
+
+// Body renders the ticket as Markdown or Jira wiki markup
+func (t ticket) Body(format string) string {
+	heading := func(s string) string { return "### " + s + "\n\n" }
+	code := func(s string) string { return "```\n" + s + "\n```\n" }
+	link := func(text, href string) string { return fmt.Sprintf("[%s](%s)", text, href) }
+	bold := "**"
+	if format == "jira" {
+		bold = "*"
+		heading = func(s string) string { return "h3. " + s + "\n\n" }
+		code = func(s string) string { return "{noformat}\n" + s + "\n{noformat}\n" }
+		link = func(text, href string) string { return fmt.Sprintf("[%s|%s]", text, href) }
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Acunetix found %s%s%s (%s) on %s.\n\n", bold, t.Name, bold, t.Severity, t.Address))
+	if t.Description != "" {
+		sb.WriteString(heading("Description"))
+		sb.WriteString(t.Description + "\n\n")
+	}
+	if t.Impact != "" {
+		sb.WriteString(heading("Impact"))
+		sb.WriteString(t.Impact + "\n\n")
+	}
+	if t.Recommendation != "" {
+		sb.WriteString(heading("Remediation"))
+		sb.WriteString(t.Recommendation + "\n\n")
+	}
+
+	sb.WriteString(heading("Evidence"))
+	for _, loc := range t.Locations {
+		sb.WriteString("* " + loc.URL)
+		if loc.Detail != "" {
+			sb.WriteString(" (" + loc.Detail + ")")
+		}
+		sb.WriteString("\n")
+		if loc.Details != "" {
+			sb.WriteString("\n" + loc.Details + "\n")
+		}
+		if loc.Request != "" {
+			sb.WriteString("\n" + code(strings.TrimSpace(loc.Request)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(t.References) > 0 {
+		sb.WriteString(heading("References"))
+		for _, ref := range t.References {
+			sb.WriteString("* " + link(ref.Rel, ref.Href) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("Finding fingerprints (used by acucli to match this ticket): " + strings.Join(t.Fingerprints, ", ") + "\n")
+	return sb.String()
+}
+
+func init() {
+	TicketCmd.Flags().String("tracker", "", "Issue tracker: jira or github")
+	TicketCmd.Flags().String("target", "", "Only the vulnerabilities of this target (ID or address)")
+	completion.Register(TicketCmd, resolver.KindTarget, "target")
+	TicketCmd.Flags().String("min-severity", "high", "Minimum severity to create tickets for: critical, high, medium, low or info")
+	TicketCmd.Flags().String("group-by", "finding", "One ticket per finding, or per vulnerability type of a target: finding or vt")
+	TicketCmd.Flags().Bool("close-fixed", false, "Close the tickets whose findings are no longer open")
+	TicketCmd.Flags().Bool("dry-run", false, "Show the tickets which would be created or closed")
+	TicketCmd.MarkFlagRequired("tracker")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// ticketCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// ticketCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
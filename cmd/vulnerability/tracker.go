@@ -0,0 +1,235 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package vulnerability
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// tracker is an issue tracker tickets are created in
+type tracker interface {
+	// Create opens a ticket and returns its key and web URL
+	Create(t ticket) (string, string, error)
+	// Comment adds a comment to a ticket
+	Comment(key string, comment string) error
+	// Close comments on a ticket and closes it
+	Close(key string, comment string) error
+}
+
+// Requests to trackers use their own client, the API client adds the Acunetix API key to every request
+var trackerClient = &http.Client{Timeout: 30 * time.Second}
+
+// Create the tracker configured under trackers.<name> in the config file
+func newTracker(name string) (tracker, error) {
+	prefix := "trackers." + name + "."
+	switch name {
+	case "jira":
+		t := jiraTracker{
+			URL:             strings.TrimSuffix(viper.GetString(prefix+"url"), "/"),
+			Email:           viper.GetString(prefix + "email"),
+			Token:           viper.GetString(prefix + "token"),
+			Project:         viper.GetString(prefix + "project"),
+			IssueType:       viper.GetString(prefix + "issue_type"),
+			Labels:          viper.GetStringSlice(prefix + "labels"),
+			CloseTransition: viper.GetString(prefix + "close_transition"),
+		}
+		if t.URL == "" || t.Token == "" || t.Project == "" {
+			return nil, fmt.Errorf("trackers.jira needs url, token and project in the config file")
+		}
+		if t.IssueType == "" {
+			t.IssueType = "Bug"
+		}
+		if t.CloseTransition == "" {
+			t.CloseTransition = "Done"
+		}
+		return t, nil
+	case "github":
+		t := githubTracker{
+			APIURL: strings.TrimSuffix(viper.GetString(prefix+"api_url"), "/"),
+			Repo:   viper.GetString(prefix + "repo"),
+			Token:  viper.GetString(prefix + "token"),
+			Labels: viper.GetStringSlice(prefix + "labels"),
+		}
+		if t.Repo == "" || t.Token == "" {
+			return nil, fmt.Errorf("trackers.github needs repo and token in the config file")
+		}
+		if t.APIURL == "" {
+			t.APIURL = "https://api.github.com"
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown tracker %q (jira, github)", name)
+}
+
+// Send a JSON request to a tracker and decode the JSON response into result when it is not nil
+func trackerRequest(method string, url string, payload interface{}, headers map[string]string, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error creating JSON request: %v", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := trackerClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status code: %d, response: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("error parsing response: %v", err)
+		}
+	}
+	return nil
+}
+
+// jiraTracker creates issues through the Jira REST API v2. With an email the token is an
+// Atlassian Cloud API token, without one it is a personal access token of Jira Server.
+type jiraTracker struct {
+	URL             string
+	Email           string
+	Token           string
+	Project         string
+	IssueType       string
+	Labels          []string
+	CloseTransition string
+}
+
+func (j jiraTracker) headers() map[string]string {
+	if j.Email != "" {
+		return map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(j.Email+":"+j.Token))}
+	}
+	return map[string]string{"Authorization": "Bearer " + j.Token}
+}
+
+func (j jiraTracker) Create(t ticket) (string, string, error) {
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.Project},
+		"issuetype":   map[string]string{"name": j.IssueType},
+		"summary":     t.Title,
+		"description": t.Body("jira"),
+	}
+	if len(j.Labels) > 0 {
+		fields["labels"] = j.Labels
+	}
+
+	var response struct {
+		Key string `json:"key"`
+	}
+	err := trackerRequest("POST", j.URL+"/rest/api/2/issue", map[string]interface{}{"fields": fields}, j.headers(), &response)
+	if err != nil {
+		return "", "", err
+	}
+	return response.Key, j.URL + "/browse/" + response.Key, nil
+}
+
+func (j jiraTracker) Comment(key string, comment string) error {
+	return trackerRequest("POST", fmt.Sprintf("%s/rest/api/2/issue/%s/comment", j.URL, key), map[string]string{"body": comment}, j.headers(), nil)
+}
+
+func (j jiraTracker) Close(key string, comment string) error {
+	if err := j.Comment(key, comment); err != nil {
+		return err
+	}
+
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	err := trackerRequest("GET", fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", j.URL, key), nil, j.headers(), &transitions)
+	if err != nil {
+		return err
+	}
+	for _, transition := range transitions.Transitions {
+		if strings.EqualFold(transition.Name, j.CloseTransition) {
+			return trackerRequest("POST", fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", j.URL, key), map[string]interface{}{
+				"transition": map[string]string{"id": transition.ID},
+			}, j.headers(), nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no transition %q", key, j.CloseTransition)
+}
+
+// githubTracker creates GitHub issues, api_url points to GitHub Enterprise when set
+type githubTracker struct {
+	APIURL string
+	Repo   string
+	Token  string
+	Labels []string
+}
+
+func (g githubTracker) headers() map[string]string {
+	return map[string]string{
+		"Authorization":        "Bearer " + g.Token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+}
+
+func (g githubTracker) Create(t ticket) (string, string, error) {
+	payload := map[string]interface{}{
+		"title": t.Title,
+		"body":  t.Body("markdown"),
+	}
+	if len(g.Labels) > 0 {
+		payload["labels"] = g.Labels
+	}
+
+	var response struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := trackerRequest("POST", fmt.Sprintf("%s/repos/%s/issues", g.APIURL, g.Repo), payload, g.headers(), &response)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprint(response.Number), response.HTMLURL, nil
+}
+
+func (g githubTracker) Comment(key string, comment string) error {
+	return trackerRequest("POST", fmt.Sprintf("%s/repos/%s/issues/%s/comments", g.APIURL, g.Repo, key), map[string]string{"body": comment}, g.headers(), nil)
+}
+
+func (g githubTracker) Close(key string, comment string) error {
+	if err := g.Comment(key, comment); err != nil {
+		return err
+	}
+	return trackerRequest("PATCH", fmt.Sprintf("%s/repos/%s/issues/%s", g.APIURL, g.Repo, key), map[string]string{
+		"state":        "closed",
+		"state_reason": "completed",
+	}, g.headers(), nil)
+}
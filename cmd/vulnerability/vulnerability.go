@@ -0,0 +1,34 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package vulnerability
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// VulnerabilityCmd represents the vulnerability command
+var VulnerabilityCmd = &cobra.Command{
+	Use:   "vulnerability",
+	Short: "Work with the vulnerabilities of all targets",
+	Long: `Works with the vulnerabilities of all targets, as listed by the server's vulnerabilities view. Example:
+
+acucli vulnerability list --format defectdojo > findings.xml
+acucli vulnerability ticket --tracker jira --min-severity high
+acucli vulnerability ticket --tracker github --target https://shop.example.com --group-by vt --close-fixed`,
+}
+
+func init() {
+	VulnerabilityCmd.AddCommand(ListCmd)
+	VulnerabilityCmd.AddCommand(TicketCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// vulnerabilityCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// vulnerabilityCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,66 @@
+package completion
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/resolver"
+)
+
+// LoadConfig reads the config before the first lookup. Completion requests skip the usual config
+// loading of the root command, as the --config flag is only parsed when the completion runs.
+var LoadConfig func() error
+
+// Func is the signature of cobra's dynamic completion functions
+type Func func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// IDs offers the IDs of the objects of a kind starting with what was typed, described by their
+// names (addresses for targets). Lookups go through the resolver cache, so pressing tab again
+// does not query the server until the cache expires.
+func IDs(kind string) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return complete(kind, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Args offers IDs of a kind for the first n positional arguments
+func Args(kind string, n int) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(kind, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Register adds the completion of IDs of a kind to flags of a command
+func Register(cmd *cobra.Command, kind string, flags ...string) {
+	for _, flag := range flags {
+		cmd.RegisterFlagCompletionFunc(flag, IDs(kind))
+	}
+}
+
+// Without a server in the config nothing is offered, errors are swallowed as there is nowhere to show them
+func complete(kind string, toComplete string) []string {
+	if LoadConfig != nil && LoadConfig() != nil {
+		return nil
+	}
+	if viper.GetString("URL") == "" {
+		return nil
+	}
+	items, err := resolver.List(kind)
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, item := range items {
+		if !strings.HasPrefix(item.ID, toComplete) {
+			continue
+		}
+		name := strings.NewReplacer("\t", " ", "\n", " ").Replace(item.Name)
+		completions = append(completions, item.ID+"\t"+name)
+	}
+	return completions
+}
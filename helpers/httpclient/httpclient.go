@@ -4,7 +4,13 @@ package httpclient
 
 import (
 	"crypto/tls"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 const (
@@ -14,6 +20,10 @@ const (
 // MyHTTPClient is a custom HTTP client with default headers and insecure skip verification.
 var MyHTTPClient http.Client
 
+// RequestObserver is called after every API request when set, e.g. to count the requests for metrics.
+// Set it before requests are made.
+var RequestObserver func(req *http.Request, resp *http.Response, err error, duration time.Duration)
+
 // headerTransport is a custom transport that sets default headers for each request.
 type headerTransport struct {
 	headers   map[string]string
@@ -34,6 +44,31 @@ func CreateHttpClient(api_key string) {
 	}
 }
 
+// Get fetches a path of the API on the configured server and returns the body,
+// any status other than 200 is an error
+func Get(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", viper.GetString("URL"), path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	resp, err := MyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error getting %s, status code: %d", strings.SplitN(path, "?", 2)[0], resp.StatusCode)
+	}
+	return body, nil
+}
+
 // RoundTrip sets default headers for each request.
 func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Set default headers
@@ -42,5 +77,10 @@ func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	// Use the underlying transport to perform the request
-	return t.Transport.RoundTrip(req)
+	start := time.Now()
+	resp, err := t.Transport.RoundTrip(req)
+	if RequestObserver != nil {
+		RequestObserver(req, resp, err, time.Since(start))
+	}
+	return resp, err
 }
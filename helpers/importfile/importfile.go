@@ -0,0 +1,298 @@
+package importfile
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported import file formats
+const (
+	FormatOpenAPI = "openapi"
+	FormatBurp    = "burp"
+	FormatPostman = "postman"
+	FormatHAR     = "har"
+)
+
+var postmanVariable = regexp.MustCompile(`{{\s*([^}\s]+)\s*}}`)
+
+// DetectFormat guesses the format of an import file from its extension and content
+func DetectFormat(filePath string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".har":
+		return FormatHAR, nil
+	case ".xml":
+		return FormatBurp, nil
+	case ".yaml", ".yml":
+		return FormatOpenAPI, nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("could not detect the format of %s, use --format", filePath)
+	}
+
+	if _, ok := doc["log"]; ok {
+		return FormatHAR, nil
+	}
+	if _, ok := doc["openapi"]; ok {
+		return FormatOpenAPI, nil
+	}
+	if _, ok := doc["swagger"]; ok {
+		return FormatOpenAPI, nil
+	}
+	if _, ok := doc["item"]; ok {
+		return FormatPostman, nil
+	}
+
+	return "", fmt.Errorf("could not detect the format of %s, use --format", filePath)
+}
+
+// ExtractAddresses reads an import file and returns the de-duplicated, sorted base URLs found in it.
+// An empty format means the format is detected from the file.
+func ExtractAddresses(filePath string, format string) ([]string, string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if format == "" {
+		format, err = DetectFormat(filePath, data)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	var urls []string
+	switch format {
+	case FormatOpenAPI:
+		urls, err = parseOpenAPI(data)
+	case FormatBurp:
+		urls, err = parseBurp(data)
+	case FormatPostman:
+		urls, err = parsePostman(data)
+	case FormatHAR:
+		urls, err = parseHAR(data)
+	default:
+		return nil, "", fmt.Errorf("unsupported format %q (openapi, burp, postman, har)", format)
+	}
+	if err != nil {
+		return nil, format, fmt.Errorf("error parsing %s file: %v", format, err)
+	}
+
+	seen := make(map[string]bool)
+	addresses := []string{}
+	for _, u := range urls {
+		address := baseAddress(u, format == FormatOpenAPI)
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	if len(addresses) == 0 {
+		return nil, format, fmt.Errorf("no absolute URLs found in %s", filePath)
+	}
+
+	return addresses, format, nil
+}
+
+// Reduce a URL to scheme://host[:port], keeping the path when keepPath is set
+func baseAddress(rawURL string, keepPath bool) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = host + ":" + port
+	}
+
+	address := fmt.Sprintf("%s://%s", u.Scheme, host)
+	if keepPath {
+		address += strings.TrimSuffix(u.Path, "/")
+	}
+	return address
+}
+
+func parseOpenAPI(data []byte) ([]string, error) {
+	// YAML is a superset of JSON, so this handles both encodings
+	var doc struct {
+		Servers []struct {
+			URL       string `yaml:"url"`
+			Variables map[string]struct {
+				Default string `yaml:"default"`
+			} `yaml:"variables"`
+		} `yaml:"servers"`
+		Host     string   `yaml:"host"`
+		BasePath string   `yaml:"basePath"`
+		Schemes  []string `yaml:"schemes"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, server := range doc.Servers {
+		serverURL := server.URL
+		for name, variable := range server.Variables {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+		}
+		urls = append(urls, serverURL)
+	}
+
+	// Swagger 2.0
+	if doc.Host != "" {
+		schemes := doc.Schemes
+		if len(schemes) == 0 {
+			schemes = []string{"https"}
+		}
+		for _, scheme := range schemes {
+			urls = append(urls, fmt.Sprintf("%s://%s%s", scheme, doc.Host, doc.BasePath))
+		}
+	}
+
+	return urls, nil
+}
+
+func parseBurp(data []byte) ([]string, error) {
+	var doc struct {
+		Items []struct {
+			URL      string `xml:"url"`
+			Host     string `xml:"host"`
+			Port     string `xml:"port"`
+			Protocol string `xml:"protocol"`
+		} `xml:"item"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, item := range doc.Items {
+		if item.URL != "" {
+			urls = append(urls, item.URL)
+			continue
+		}
+		if item.Host != "" && item.Protocol != "" {
+			urls = append(urls, fmt.Sprintf("%s://%s:%s", item.Protocol, item.Host, item.Port))
+		}
+	}
+
+	return urls, nil
+}
+
+type postmanItem struct {
+	Request json.RawMessage `json:"request"`
+	Item    []postmanItem   `json:"item"`
+}
+
+func parsePostman(data []byte) ([]string, error) {
+	var doc struct {
+		Item     []postmanItem `json:"item"`
+		Variable []struct {
+			Key   string      `json:"key"`
+			Value interface{} `json:"value"`
+		} `json:"variable"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]string)
+	for _, v := range doc.Variable {
+		variables[v.Key] = fmt.Sprint(v.Value)
+	}
+
+	var urls []string
+	var walk func(items []postmanItem)
+	walk = func(items []postmanItem) {
+		for _, item := range items {
+			if rawURL := postmanRequestURL(item.Request); rawURL != "" {
+				rawURL = postmanVariable.ReplaceAllStringFunc(rawURL, func(match string) string {
+					name := postmanVariable.FindStringSubmatch(match)[1]
+					if value, ok := variables[name]; ok {
+						return value
+					}
+					return match
+				})
+				// Requests still containing unresolved variables cannot be turned into targets
+				if !strings.Contains(rawURL, "{{") {
+					urls = append(urls, rawURL)
+				}
+			}
+			walk(item.Item)
+		}
+	}
+	walk(doc.Item)
+
+	return urls, nil
+}
+
+// Postman stores a request either as a URL string or as an object whose url
+// is itself a string or an object with a raw field
+func postmanRequestURL(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var request struct {
+		URL json.RawMessage `json:"url"`
+	}
+	if err := json.Unmarshal(raw, &request); err != nil || len(request.URL) == 0 {
+		return ""
+	}
+	if err := json.Unmarshal(request.URL, &s); err == nil {
+		return s
+	}
+
+	var u struct {
+		Raw string `json:"raw"`
+	}
+	if err := json.Unmarshal(request.URL, &u); err == nil {
+		return u.Raw
+	}
+	return ""
+}
+
+func parseHAR(data []byte) ([]string, error) {
+	var doc struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, entry := range doc.Log.Entries {
+		urls = append(urls, entry.Request.URL)
+	}
+
+	return urls, nil
+}
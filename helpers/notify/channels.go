@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Notifications are sent with their own client, the API client adds the Acunetix API key to every request
+var webhookClient = &http.Client{Timeout: 15 * time.Second}
+
+// Colors of the chat messages per event
+var eventColors = map[string]string{
+	EventScanCompleted: "2EB67D",
+	EventScanFailed:    "E01E5A",
+	EventGateFailed:    "E01E5A",
+	EventNewCritical:   "7B1FA2",
+}
+
+func postJSON(url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+	return post(url, body, headers)
+}
+
+func post(url string, body []byte, headers map[string]string) error {
+	if url == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status code: %d, response: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// Sign a payload with HMAC-SHA256, sent as X-Acucli-Signature: sha256=<hex>
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Generic webhook: the event as JSON, signed when a secret is configured
+func sendWebhook(config Config, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error creating JSON request: %v", err)
+	}
+
+	headers := map[string]string{"X-Acucli-Event": event.Type}
+	if config.Secret != "" {
+		headers["X-Acucli-Signature"] = sign(config.Secret, body)
+	}
+	return post(config.URL, body, headers)
+}
+
+// Slack incoming webhook payload, Mattermost accepts the same format
+func sendSlack(config Config, event Event) error {
+	return postJSON(config.URL, map[string]interface{}{
+		"text": fmt.Sprintf("*%s*\n%s", event.Title(), event.Text()),
+		"attachments": []map[string]interface{}{
+			{"color": "#" + eventColors[event.Type], "fallback": event.Title()},
+		},
+	}, nil)
+}
+
+// Microsoft Teams incoming webhook payload
+func sendTeams(config Config, event Event) error {
+	return postJSON(config.URL, map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    event.Title(),
+		"themeColor": eventColors[event.Type],
+		"title":      event.Title(),
+		// Teams renders the text as Markdown, two spaces end a line
+		"text": strings.ReplaceAll(event.Text(), "\n", "  \n"),
+	}, nil)
+}
+
+// Email through an SMTP server, STARTTLS is used when the server offers it
+func sendEmail(config Config, event Event) error {
+	if config.SMTPHost == "" || config.From == "" || len(config.To) == 0 {
+		return fmt.Errorf("smtp_host, from and to are required")
+	}
+	port := config.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", config.From))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(config.To, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", event.Title()))
+	msg.WriteString(fmt.Sprintf("Date: %s\r\n", event.Time.Format(time.RFC1123Z)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(event.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
+	}
+	return smtp.SendMail(fmt.Sprintf("%s:%d", config.SMTPHost, port), auth, config.From, config.To, []byte(msg.String()))
+}
@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Event types
+const (
+	EventScanCompleted = "scan_completed"
+	EventScanFailed    = "scan_failed"
+	EventGateFailed    = "gate_failed"
+	EventNewCritical   = "new_critical"
+)
+
+// Config is one entry of the notifications list in the config file
+type Config struct {
+	Name   string   `mapstructure:"name"`
+	Type   string   `mapstructure:"type"` // webhook, slack, teams, mattermost or email
+	URL    string   `mapstructure:"url"`
+	Secret string   `mapstructure:"secret"` // HMAC key of webhook payloads
+	Events []string `mapstructure:"events"` // empty means every event
+
+	SMTPHost string   `mapstructure:"smtp_host"`
+	SMTPPort int      `mapstructure:"smtp_port"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+// Finding is a vulnerability listed in an event
+type Finding struct {
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`
+	AffectsURL string `json:"affects_url"`
+}
+
+// Event is something that happened to a scan
+type Event struct {
+	Type     string                   `json:"event"`
+	Time     time.Time                `json:"time"`
+	TargetID string                   `json:"target_id,omitempty"`
+	Address  string                   `json:"address,omitempty"`
+	ScanID   string                   `json:"scan_id,omitempty"`
+	Status   string                   `json:"status,omitempty"`
+	Message  string                   `json:"message"`
+	Counts   *scandata.SeverityCounts `json:"counts,omitempty"`
+	Findings []Finding                `json:"findings,omitempty"`
+
+	// Fingerprints of the new_critical findings, stored as notified once the event is delivered
+	fingerprints []string
+}
+
+// Title is a one line summary of the event
+func (e Event) Title() string {
+	switch e.Type {
+	case EventScanCompleted:
+		return fmt.Sprintf("Scan of %s completed", e.Address)
+	case EventScanFailed:
+		return fmt.Sprintf("Scan of %s failed", e.Address)
+	case EventGateFailed:
+		return fmt.Sprintf("Security gate failed for %s", e.Address)
+	case EventNewCritical:
+		return fmt.Sprintf("New critical vulnerabilities on %s", e.Address)
+	}
+	return e.Type
+}
+
+// Text is the plain text body of the event
+func (e Event) Text() string {
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+	if e.Counts != nil {
+		sb.WriteString(fmt.Sprintf("\nCritical: %d, High: %d, Medium: %d, Low: %d, Info: %d", e.Counts.Critical, e.Counts.High, e.Counts.Medium, e.Counts.Low, e.Counts.Info))
+	}
+	for _, finding := range e.Findings {
+		sb.WriteString(fmt.Sprintf("\n- [%s] %s: %s", finding.Severity, finding.Name, finding.AffectsURL))
+	}
+	if e.ScanID != "" {
+		sb.WriteString(fmt.Sprintf("\nScan ID: %s", e.ScanID))
+	}
+	return sb.String()
+}
+
+// Configs reads the notifications configured in the config file
+func Configs() ([]Config, error) {
+	var configs []Config
+	if err := viper.UnmarshalKey("notifications", &configs); err != nil {
+		return nil, fmt.Errorf("invalid notifications config: %v", err)
+	}
+	return configs, nil
+}
+
+// Wants reports whether the notification is configured for the event type
+func (c Config) Wants(eventType string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Wanted reports whether any notification is configured for the event type
+func Wanted(eventType string) bool {
+	configs, err := Configs()
+	if err != nil {
+		return false
+	}
+	for _, config := range configs {
+		if config.Wants(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// Send delivers the event to every notification configured for it and
+// returns the number of notifications sent and the errors of the failed ones
+func Send(event Event) (int, []string) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	configs, err := Configs()
+	if err != nil {
+		return 0, []string{err.Error()}
+	}
+
+	sent := 0
+	var errors []string
+	for _, config := range configs {
+		if !config.Wants(event.Type) {
+			continue
+		}
+		if err := deliver(config, event); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", config.label(), err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors
+}
+
+func deliver(config Config, event Event) error {
+	switch config.Type {
+	case "webhook":
+		return sendWebhook(config, event)
+	case "slack", "mattermost":
+		return sendSlack(config, event)
+	case "teams":
+		return sendTeams(config, event)
+	case "email":
+		return sendEmail(config, event)
+	}
+	return fmt.Errorf("unknown notification type %q (webhook, slack, teams, mattermost, email)", config.Type)
+}
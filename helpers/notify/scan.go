@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tosbaa/acucli/helpers/scandata"
+	"github.com/tosbaa/acucli/helpers/store"
+)
+
+// Bucket of the critical findings already reported by new_critical events
+const bucketNotified = "notified_criticals"
+
+// GateDisabled is the gate level when no --fail-on severity is given
+const GateDisabled = -1
+
+// ParseGate parses a --fail-on severity (critical, high, medium, low), an empty value disables the gate
+func ParseGate(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "none" {
+		return GateDisabled, nil
+	}
+	severity, ok := scandata.SeverityValue(value)
+	if !ok || severity == scandata.SeverityInfo {
+		return GateDisabled, fmt.Errorf("invalid severity %q (critical, high, medium, low)", value)
+	}
+	return severity, nil
+}
+
+// ScanOutcome is the state of a finished scan and the events it raises
+type ScanOutcome struct {
+	Scan       scandata.Scan
+	Counts     scandata.SeverityCounts
+	GateFailed bool
+	Events     []Event
+	// Problems which did not stop the outcome, e.g. the local store could not be read
+	Warnings []string
+}
+
+// FinishedScanEvents fetches a finished scan and its vulnerabilities and builds its events:
+// scan_completed or scan_failed, gate_failed when a vulnerability reaches the gate severity,
+// and new_critical for the critical findings not reported before when a notification wants it.
+func FinishedScanEvents(scanID string, gate int) (ScanOutcome, error) {
+	var outcome ScanOutcome
+
+	scan, err := scandata.FetchScan(scanID)
+	if err != nil {
+		return outcome, err
+	}
+	outcome.Scan = scan
+	base := Event{Time: time.Now(), TargetID: scan.TargetID, Address: scan.Target.Address, ScanID: scan.ScanID, Status: scan.CurrentSession.Status}
+
+	if scan.CurrentSession.Status != "completed" {
+		event := base
+		event.Type = EventScanFailed
+		event.Message = fmt.Sprintf("Scan ended with status %s", scan.CurrentSession.Status)
+		outcome.Events = append(outcome.Events, event)
+		return outcome, nil
+	}
+
+	results, err := scandata.FetchResults(scanID)
+	if err != nil {
+		return outcome, err
+	}
+	result, err := scandata.LatestResult(scan, results)
+	if err != nil {
+		return outcome, err
+	}
+	vulnerabilities, err := scandata.FetchVulnerabilities(scanID, result.ResultID)
+	if err != nil {
+		return outcome, err
+	}
+	scandata.SortVulnerabilities(vulnerabilities)
+	outcome.Counts = scandata.CountSeverities(vulnerabilities)
+
+	completed := base
+	completed.Type = EventScanCompleted
+	completed.Message = fmt.Sprintf("Scan found %d vulnerabilities", outcome.Counts.Total)
+	completed.Counts = &outcome.Counts
+	outcome.Events = append(outcome.Events, completed)
+
+	if gate != GateDisabled {
+		var failing []Finding
+		for _, v := range vulnerabilities {
+			if v.Severity >= gate {
+				failing = append(failing, findingOf(v))
+			}
+		}
+		if len(failing) > 0 {
+			outcome.GateFailed = true
+			event := base
+			event.Type = EventGateFailed
+			event.Message = fmt.Sprintf("%d vulnerabilities at or above %s", len(failing), scandata.SeverityName(gate))
+			event.Counts = &outcome.Counts
+			event.Findings = failing
+			outcome.Events = append(outcome.Events, event)
+		}
+	}
+
+	if Wanted(EventNewCritical) {
+		critical, fingerprints, err := newCriticals(scan.Target.Address, vulnerabilities)
+		if err != nil {
+			outcome.Warnings = append(outcome.Warnings, fmt.Sprintf("new_critical skipped, error checking reported findings: %v", err))
+		} else if len(critical) > 0 {
+			event := base
+			event.Type = EventNewCritical
+			event.Message = fmt.Sprintf("%d new critical vulnerabilities", len(critical))
+			event.Findings = critical
+			event.fingerprints = fingerprints
+			outcome.Events = append(outcome.Events, event)
+		}
+	}
+
+	return outcome, nil
+}
+
+func findingOf(v scandata.Vulnerability) Finding {
+	return Finding{Name: v.Name, Severity: v.SeverityName(), AffectsURL: v.AffectsURL}
+}
+
+// Critical findings which were not reported before and their fingerprints. Findings are matched by
+// target address so targets which are re-created for every scan (auto mode) are not reported again.
+// They are only stored as reported by Dispatch, once the event is delivered.
+func newCriticals(address string, vulnerabilities []scandata.Vulnerability) ([]Finding, []string, error) {
+	db, err := store.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+
+	var findings []Finding
+	var fingerprints []string
+	target := strings.TrimSuffix(strings.ToLower(address), "/")
+	for _, v := range vulnerabilities {
+		if v.Severity != scandata.SeverityCritical {
+			continue
+		}
+		key := v.Fingerprint(target)
+		var notifiedAt time.Time
+		found, err := db.Get(bucketNotified, key, &notifiedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if found {
+			continue
+		}
+		findings = append(findings, findingOf(v))
+		fingerprints = append(fingerprints, key)
+	}
+	return findings, fingerprints, nil
+}
+
+// Store the findings of a delivered new_critical event as reported
+func markNotified(fingerprints []string) error {
+	db, err := store.Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	now := time.Now()
+	for _, key := range fingerprints {
+		if err := db.Put(bucketNotified, key, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dispatch sends the events and returns a summary per event for the command output
+func Dispatch(events []Event) []map[string]interface{} {
+	var summary []map[string]interface{}
+	for _, event := range events {
+		sent, errors := Send(event)
+		if event.Type == EventNewCritical && sent > 0 {
+			if err := markNotified(event.fingerprints); err != nil {
+				errors = append(errors, fmt.Sprintf("error storing notified findings: %v", err))
+			}
+		}
+		entry := map[string]interface{}{"event": event.Type, "sent": sent}
+		if len(errors) > 0 {
+			entry["errors"] = errors
+		}
+		summary = append(summary, entry)
+	}
+	return summary
+}
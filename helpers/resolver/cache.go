@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Default lifetime of cached lookups, set resolver_cache_ttl (seconds) in the config to change it, 0 disables the cache
+const defaultCacheTTL = 300
+
+type cacheEntry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Items     []Item    `json:"items"`
+}
+
+// The cache file holds the entries per server URL and kind
+type cacheFile map[string]map[string]cacheEntry
+
+// Guards the cache file against lookups running at the same time, e.g. the jobs of acucli serve
+var cacheMu sync.Mutex
+
+// CachePath returns the path of the local lookup cache
+func CachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "acucli", "resolver.json")
+}
+
+func cacheTTL() time.Duration {
+	ttl := defaultCacheTTL
+	if viper.IsSet("resolver_cache_ttl") {
+		ttl = viper.GetInt("resolver_cache_ttl")
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+func readCacheFile() cacheFile {
+	cache := make(cacheFile)
+	data, err := os.ReadFile(CachePath())
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(cacheFile)
+	}
+	return cache
+}
+
+func loadCache(kind string) ([]Item, bool) {
+	ttl := cacheTTL()
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	entry, ok := readCacheFile()[viper.GetString("URL")][kind]
+	if !ok || time.Since(entry.FetchedAt) > ttl {
+		return nil, false
+	}
+	return entry.Items, true
+}
+
+// Store the items of a kind, errors are ignored as the cache is only an optimisation
+func saveCache(kind string, items []Item) {
+	if cacheTTL() <= 0 {
+		return
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache := readCacheFile()
+	server := viper.GetString("URL")
+	if cache[server] == nil {
+		cache[server] = make(map[string]cacheEntry)
+	}
+	cache[server][kind] = cacheEntry{FetchedAt: time.Now(), Items: items}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	path := CachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+// Invalidate drops the cached items of a kind, used after objects are created, renamed or removed
+func Invalidate(kind string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache := readCacheFile()
+	server := viper.GetString("URL")
+	if _, ok := cache[server][kind]; !ok {
+		return
+	}
+	delete(cache[server], kind)
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	os.WriteFile(CachePath(), data, 0600)
+}
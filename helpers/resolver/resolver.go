@@ -0,0 +1,247 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/tosbaa/acucli/helpers/filehelper"
+	"github.com/tosbaa/acucli/helpers/httpclient"
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Kinds of objects that can be resolved by name
+const (
+	KindTarget         = "target"
+	KindGroup          = "target_group"
+	KindScanProfile    = "scan_profile"
+	KindReportTemplate = "report_template"
+	KindExportType     = "export_type"
+	KindExcludedHours  = "excluded_hours"
+)
+
+// Item is an object with its ID and the name it can be referred to by.
+// The name of a target is its address.
+type Item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NotFoundError is returned when no object matches a name
+type NotFoundError struct {
+	Kind  string
+	Value string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", kindLabel(e.Kind), e.Value)
+}
+
+// AmbiguousError is returned when several objects match a name
+type AmbiguousError struct {
+	Kind    string
+	Value   string
+	Matches []Item
+}
+
+func (e *AmbiguousError) Error() string {
+	ids := make([]string, 0, len(e.Matches))
+	for _, item := range e.Matches {
+		ids = append(ids, item.ID)
+	}
+	return fmt.Sprintf("%s %q is ambiguous, use one of the IDs: %s", kindLabel(e.Kind), e.Value, strings.Join(ids, ", "))
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsUUID reports whether a value is already an ID
+func IsUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+// Resolve returns the ID of the object of the given kind referred to by value,
+// which is either an ID or a name (an address for targets).
+// Lookups are served from the local cache, a name which is not found there is
+// looked up again on the server in case the object was created since.
+func Resolve(kind string, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("empty %s", kindLabel(kind))
+	}
+	if IsUUID(value) {
+		return value, nil
+	}
+
+	items, cached, err := list(kind, false)
+	if err != nil {
+		return "", err
+	}
+	id, err := match(kind, value, items)
+	if _, notFound := err.(*NotFoundError); notFound && cached {
+		items, _, err = list(kind, true)
+		if err != nil {
+			return "", err
+		}
+		id, err = match(kind, value, items)
+	}
+	return id, err
+}
+
+// ResolveAll resolves every value and reports all the values which could not be resolved
+func ResolveAll(kind string, values []string) ([]string, error) {
+	var ids []string
+	var failures []string
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		id, err := Resolve(kind, value)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return ids, nil
+}
+
+// ReadStdin reads IDs or names line by line from stdin and resolves them
+func ReadStdin(kind string) ([]string, error) {
+	input := filehelper.ReadStdin()
+	if len(input) == 0 {
+		return nil, nil
+	}
+	return ResolveAll(kind, input)
+}
+
+// List returns the objects of a kind, from the cache when it is fresh
+func List(kind string) ([]Item, error) {
+	items, _, err := list(kind, false)
+	return items, err
+}
+
+func list(kind string, refresh bool) ([]Item, bool, error) {
+	if !refresh {
+		if items, ok := loadCache(kind); ok {
+			return items, true, nil
+		}
+	}
+
+	items, err := fetch(kind)
+	if err != nil {
+		return nil, false, err
+	}
+	saveCache(kind, items)
+	return items, false, nil
+}
+
+// Find the single item matching value. Names are compared case-insensitively,
+// targets are also matched by host when no address matches.
+func match(kind string, value string, items []Item) (string, error) {
+	var matches []Item
+	if kind == KindTarget {
+		want := normalizeAddress(value)
+		for _, item := range items {
+			if normalizeAddress(item.Name) == want {
+				matches = append(matches, item)
+			}
+		}
+		if len(matches) == 0 && !strings.Contains(value, "://") {
+			for _, item := range items {
+				if u, err := url.Parse(item.Name); err == nil && strings.EqualFold(u.Host, want) {
+					matches = append(matches, item)
+				}
+			}
+		}
+	} else {
+		for _, item := range items {
+			if strings.EqualFold(item.Name, value) {
+				matches = append(matches, item)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", &NotFoundError{Kind: kind, Value: value}
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", &AmbiguousError{Kind: kind, Value: value, Matches: matches}
+	}
+}
+
+func normalizeAddress(address string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(address)), "/")
+}
+
+func kindLabel(kind string) string {
+	return strings.ReplaceAll(kind, "_", " ")
+}
+
+// Fetch the objects of a kind from the server
+func fetch(kind string) ([]Item, error) {
+	switch kind {
+	case KindTarget:
+		return fetchPaginated("/targets", "targets", "target_id", "address")
+	case KindGroup:
+		return fetchPaginated("/target_groups", "groups", "group_id", "name")
+	case KindScanProfile:
+		return fetchList("/scanning_profiles", "scanning_profiles", "profile_id", "name")
+	case KindReportTemplate:
+		return fetchList("/report_templates", "templates", "template_id", "name")
+	case KindExportType:
+		return fetchList("/export_types", "templates", "export_id", "name")
+	case KindExcludedHours:
+		return fetchList("/excluded_hours_profiles", "values", "excluded_hours_id", "name")
+	}
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
+
+// Fetch a list which is not paginated
+func fetchList(path string, listKey string, idKey string, nameKey string) ([]Item, error) {
+	body, err := httpclient.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var response map[string]json.RawMessage
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %v", err)
+	}
+	var entries []map[string]interface{}
+	if raw, ok := response[listKey]; ok {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("error parsing response: %v", err)
+		}
+	}
+	return toItems(entries, idKey, nameKey), nil
+}
+
+// Fetch every page of a list
+func fetchPaginated(path string, listKey string, idKey string, nameKey string) ([]Item, error) {
+	entries, err := scandata.FetchList[map[string]interface{}](path, listKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return toItems(entries, idKey, nameKey), nil
+}
+
+func toItems(entries []map[string]interface{}, idKey string, nameKey string) []Item {
+	items := make([]Item, 0, len(entries))
+	for _, entry := range entries {
+		id, _ := entry[idKey].(string)
+		name, _ := entry[nameKey].(string)
+		if id != "" {
+			items = append(items, Item{ID: id, Name: name})
+		}
+	}
+	return items
+}
@@ -0,0 +1,372 @@
+package scandata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tosbaa/acucli/helpers/httpclient"
+)
+
+// Scan is the part of a scan used to describe findings
+type Scan struct {
+	ScanID      string `json:"scan_id"`
+	TargetID    string `json:"target_id"`
+	ProfileID   string `json:"profile_id"`
+	ProfileName string `json:"profile_name"`
+	Target      struct {
+		Address     string `json:"address"`
+		Description string `json:"description"`
+		Criticality int    `json:"criticality"`
+	} `json:"target"`
+	CurrentSession struct {
+		ScanSessionID  string         `json:"scan_session_id"`
+		Status         string         `json:"status"`
+		StartDate      string         `json:"start_date"`
+		Progress       int            `json:"progress"`
+		SeverityCounts map[string]int `json:"severity_counts"`
+	} `json:"current_session"`
+}
+
+// Target is a target with the fields used by reports and metrics
+type Target struct {
+	TargetID    string `json:"target_id"`
+	Address     string `json:"address"`
+	Description string `json:"description"`
+	Criticality int    `json:"criticality"`
+}
+
+// TargetGroup is a target group
+type TargetGroup struct {
+	GroupID     string `json:"group_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TargetCount int    `json:"target_count"`
+}
+
+// Report is a generated report
+type Report struct {
+	ReportID       string   `json:"report_id"`
+	TemplateName   string   `json:"template_name"`
+	Status         string   `json:"status"`
+	GenerationDate string   `json:"generation_date"`
+	Download       []string `json:"download"`
+	Source         struct {
+		Description string   `json:"description"`
+		ListType    string   `json:"list_type"`
+		IDList      []string `json:"id_list"`
+	} `json:"source"`
+}
+
+// Result is one run of a scan
+type Result struct {
+	ResultID  string `json:"result_id"`
+	ScanID    string `json:"scan_id"`
+	Status    string `json:"status"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// Reference is a link to more information about a vulnerability
+type Reference struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// Vulnerability is a finding of a scan result. The description fields are only
+// filled in when the details were fetched.
+type Vulnerability struct {
+	VulnID        string   `json:"vuln_id"`
+	VtID          string   `json:"vt_id"`
+	TargetID      string   `json:"target_id,omitempty"`
+	Name          string   `json:"vt_name"`
+	Severity      int      `json:"severity"`
+	Confidence    int      `json:"confidence"`
+	Criticality   int      `json:"criticality"`
+	AffectsURL    string   `json:"affects_url"`
+	AffectsDetail string   `json:"affects_detail"`
+	Status        string   `json:"status"`
+	LastSeen      string   `json:"last_seen"`
+	Tags          []string `json:"tags"`
+
+	Description    string      `json:"description,omitempty"`
+	Impact         string      `json:"impact,omitempty"`
+	Recommendation string      `json:"recommendation,omitempty"`
+	Details        string      `json:"details,omitempty"`
+	Request        string      `json:"request,omitempty"`
+	References     []Reference `json:"references,omitempty"`
+}
+
+// SeverityName returns the name of the vulnerability's severity
+func (v Vulnerability) SeverityName() string {
+	return SeverityName(v.Severity)
+}
+
+// Fingerprint identifies the same finding across scan results: the vulnerability type at the same place
+// of a target. The target is given by ID, or by address to match findings of re-created targets.
+func (v Vulnerability) Fingerprint(target string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{target, v.VtID, v.AffectsURL, v.AffectsDetail}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Technology is a technology detected by a scan result
+type Technology struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`
+	LocURL   string `json:"loc_url"`
+	Outdated bool   `json:"outdated"`
+}
+
+// Get a JSON object from the API
+func getJSON(path string, target interface{}) error {
+	body, err := httpclient.Get(path)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, target)
+	if err != nil {
+		return fmt.Errorf("error parsing response: %v", err)
+	}
+	return nil
+}
+
+// FetchScan gets a scan
+func FetchScan(scanID string) (Scan, error) {
+	var scan Scan
+	err := getJSON(fmt.Sprintf("/scans/%s", scanID), &scan)
+	return scan, err
+}
+
+// FetchResults gets the results of a scan, the most recent first
+func FetchResults(scanID string) ([]Result, error) {
+	return FetchList[Result](fmt.Sprintf("/scans/%s/results", scanID), "results", nil)
+}
+
+// LatestResult returns the result of the scan's current session, or else the most recent result
+func LatestResult(scan Scan, results []Result) (Result, error) {
+	for _, result := range results {
+		if result.ResultID == scan.CurrentSession.ScanSessionID {
+			return result, nil
+		}
+	}
+	if len(results) > 0 {
+		return results[0], nil
+	}
+	return Result{}, fmt.Errorf("scan %s has no results", scan.ScanID)
+}
+
+// FetchList gets every item of a list, following the pagination cursors.
+// key is the field of the response holding the items, params are added to the query of every page.
+func FetchList[T any](path string, key string, params url.Values) ([]T, error) {
+	var items []T
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		for name, values := range params {
+			query[name] = values
+		}
+		query.Set("l", "100")
+		if cursor != "" {
+			query.Set("c", cursor)
+		}
+
+		var page map[string]json.RawMessage
+		err := getJSON(path+"?"+query.Encode(), &page)
+		if err != nil {
+			return nil, err
+		}
+		var pageItems []T
+		if raw, ok := page[key]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, fmt.Errorf("error parsing response: %v", err)
+			}
+		}
+		items = append(items, pageItems...)
+
+		// The second cursor points to the next page when there is one
+		next := ""
+		var pagination struct {
+			Cursors []any `json:"cursors"`
+		}
+		if raw, ok := page["pagination"]; ok && json.Unmarshal(raw, &pagination) == nil {
+			if len(pagination.Cursors) > 1 && pagination.Cursors[1] != nil {
+				next = fmt.Sprint(pagination.Cursors[1])
+			}
+		}
+		if len(pageItems) == 0 || next == "" || next == cursor {
+			break
+		}
+		cursor = next
+	}
+
+	return items, nil
+}
+
+// FetchScans gets every scan
+func FetchScans() ([]Scan, error) {
+	return FetchList[Scan]("/scans", "scans", nil)
+}
+
+// FetchTargets gets every target
+func FetchTargets() ([]Target, error) {
+	return FetchList[Target]("/targets", "targets", nil)
+}
+
+// FetchTargetGroups gets every target group
+func FetchTargetGroups() ([]TargetGroup, error) {
+	return FetchList[TargetGroup]("/target_groups", "groups", nil)
+}
+
+// FetchReports gets every report
+func FetchReports() ([]Report, error) {
+	return FetchList[Report]("/reports", "reports", nil)
+}
+
+// FetchGroupTargets gets the IDs of the targets of a target group
+func FetchGroupTargets(groupID string) ([]string, error) {
+	var response struct {
+		TargetIDList []string `json:"target_id_list"`
+	}
+	err := getJSON(fmt.Sprintf("/target_groups/%s/targets", groupID), &response)
+	return response.TargetIDList, err
+}
+
+// FetchVulnerabilities gets every vulnerability of a scan result
+func FetchVulnerabilities(scanID string, resultID string) ([]Vulnerability, error) {
+	return FetchList[Vulnerability](fmt.Sprintf("/scans/%s/results/%s/vulnerabilities", scanID, resultID), "vulnerabilities", nil)
+}
+
+// FetchAllVulnerabilities gets the vulnerabilities of every target matching a query (e.g. status:open;severity:3,4)
+func FetchAllVulnerabilities(query string) ([]Vulnerability, error) {
+	params := url.Values{}
+	if query != "" {
+		params.Set("q", query)
+	}
+	return FetchList[Vulnerability]("/vulnerabilities", "vulnerabilities", params)
+}
+
+// FetchVulnerability gets a vulnerability of the vulnerabilities list with its description, impact and recommendation
+func FetchVulnerability(vulnID string) (Vulnerability, error) {
+	var vulnerability Vulnerability
+	err := getJSON(fmt.Sprintf("/vulnerabilities/%s", vulnID), &vulnerability)
+	return vulnerability, err
+}
+
+// FetchVulnerabilityDetails gets a vulnerability with its description, impact and recommendation
+func FetchVulnerabilityDetails(scanID string, resultID string, vulnID string) (Vulnerability, error) {
+	var vulnerability Vulnerability
+	err := getJSON(fmt.Sprintf("/scans/%s/results/%s/vulnerabilities/%s", scanID, resultID, vulnID), &vulnerability)
+	return vulnerability, err
+}
+
+// FetchTechnologies gets the technologies detected by a scan result
+func FetchTechnologies(scanID string, resultID string) ([]Technology, error) {
+	var response struct {
+		Technologies []Technology `json:"technologies"`
+	}
+	err := getJSON(fmt.Sprintf("/scans/%s/results/%s/technologies", scanID, resultID), &response)
+	return response.Technologies, err
+}
+
+// LoadResult fetches a scan, one of its results (the latest when resultID is empty) and the result's vulnerabilities.
+// With details the description, recommendation and evidence of every vulnerability is fetched as well.
+func LoadResult(scanID string, resultID string, details bool) (Scan, Result, []Vulnerability, error) {
+	var result Result
+
+	scan, err := FetchScan(scanID)
+	if err != nil {
+		return scan, result, nil, err
+	}
+
+	results, err := FetchResults(scanID)
+	if err != nil {
+		return scan, result, nil, err
+	}
+	if resultID == "" {
+		result, err = LatestResult(scan, results)
+		if err != nil {
+			return scan, result, nil, err
+		}
+	} else {
+		found := false
+		for _, r := range results {
+			if r.ResultID == resultID {
+				result = r
+				found = true
+			}
+		}
+		if !found {
+			return scan, result, nil, fmt.Errorf("result %s not found for scan %s", resultID, scanID)
+		}
+	}
+
+	vulnerabilities, err := FetchVulnerabilities(scanID, result.ResultID)
+	if err != nil {
+		return scan, result, nil, err
+	}
+	for i := range vulnerabilities {
+		if vulnerabilities[i].TargetID == "" {
+			vulnerabilities[i].TargetID = scan.TargetID
+		}
+	}
+	if details {
+		for i, v := range vulnerabilities {
+			detailed, err := FetchVulnerabilityDetails(scanID, result.ResultID, v.VulnID)
+			if err != nil {
+				return scan, result, nil, err
+			}
+			vulnerabilities[i] = v.WithDetails(detailed)
+		}
+	}
+	SortVulnerabilities(vulnerabilities)
+
+	return scan, result, vulnerabilities, nil
+}
+
+// WithDetails keeps the list fields of the vulnerability and adds the description fields of the detailed one
+func (v Vulnerability) WithDetails(detailed Vulnerability) Vulnerability {
+	v.Description = detailed.Description
+	v.Impact = detailed.Impact
+	v.Recommendation = detailed.Recommendation
+	v.Details = detailed.Details
+	v.Request = detailed.Request
+	v.References = detailed.References
+	if len(detailed.Tags) > 0 {
+		v.Tags = detailed.Tags
+	}
+	return v
+}
+
+// CWE returns the CWE number of the vulnerability from its tags (CWE-89), or 0
+func (v Vulnerability) CWE() int {
+	for _, tag := range v.Tags {
+		if strings.HasPrefix(strings.ToUpper(tag), "CWE-") {
+			if n, err := strconv.Atoi(tag[4:]); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// SortVulnerabilities orders vulnerabilities by severity, most severe first, then by name and URL
+func SortVulnerabilities(vulnerabilities []Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		a, b := vulnerabilities[i], vulnerabilities[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.AffectsURL < b.AffectsURL
+	})
+}
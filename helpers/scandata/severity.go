@@ -0,0 +1,76 @@
+package scandata
+
+// Severity values used by the server
+const (
+	SeverityInfo     = 0
+	SeverityLow      = 1
+	SeverityMedium   = 2
+	SeverityHigh     = 3
+	SeverityCritical = 4
+)
+
+// Severity names, most severe first
+var SeverityNames = []string{"critical", "high", "medium", "low", "info"}
+
+// SeverityName returns the name of a severity value
+func SeverityName(severity int) string {
+	switch severity {
+	case SeverityCritical:
+		return "critical"
+	case SeverityHigh:
+		return "high"
+	case SeverityMedium:
+		return "medium"
+	case SeverityLow:
+		return "low"
+	}
+	return "info"
+}
+
+// SeverityValue returns the severity value of a name, and false for unknown names
+func SeverityValue(name string) (int, bool) {
+	switch name {
+	case "critical":
+		return SeverityCritical, true
+	case "high":
+		return SeverityHigh, true
+	case "medium":
+		return SeverityMedium, true
+	case "low":
+		return SeverityLow, true
+	case "info", "informational":
+		return SeverityInfo, true
+	}
+	return 0, false
+}
+
+// SeverityCounts is the number of vulnerabilities per severity
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Info     int `json:"info"`
+	Total    int `json:"total"`
+}
+
+// CountSeverities counts the vulnerabilities per severity
+func CountSeverities(vulnerabilities []Vulnerability) SeverityCounts {
+	var counts SeverityCounts
+	for _, v := range vulnerabilities {
+		switch v.Severity {
+		case SeverityCritical:
+			counts.Critical++
+		case SeverityHigh:
+			counts.High++
+		case SeverityMedium:
+			counts.Medium++
+		case SeverityLow:
+			counts.Low++
+		default:
+			counts.Info++
+		}
+		counts.Total++
+	}
+	return counts
+}
@@ -0,0 +1,15 @@
+package scandata
+
+import (
+	"regexp"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+var spacePattern = regexp.MustCompile(`\s+`)
+
+// PlainText converts the HTML descriptions returned by the server to a single line of text
+func PlainText(s string) string {
+	s = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", "\"", "&#39;", "'", "&amp;", "&").Replace(tagPattern.ReplaceAllString(s, " "))
+	return strings.TrimSpace(spacePattern.ReplaceAllString(s, " "))
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// TargetRecord is the last known state of a target
+type TargetRecord struct {
+	TargetID    string    `json:"target_id"`
+	Address     string    `json:"address"`
+	Description string    `json:"description"`
+	Criticality int       `json:"criticality"`
+	SyncedAt    time.Time `json:"synced_at"`
+}
+
+// FindingRef is a vulnerability as found by one scan result
+type FindingRef struct {
+	Fingerprint   string `json:"fingerprint"`
+	VtID          string `json:"vt_id"`
+	Name          string `json:"name"`
+	Severity      int    `json:"severity"`
+	AffectsURL    string `json:"affects_url"`
+	AffectsDetail string `json:"affects_detail"`
+}
+
+// ResultSnapshot is a completed scan result with its vulnerabilities.
+// Snapshots are keyed by target ID and date so the results of a target are read in order.
+type ResultSnapshot struct {
+	ResultID    string       `json:"result_id"`
+	ScanID      string       `json:"scan_id"`
+	TargetID    string       `json:"target_id"`
+	Address     string       `json:"address"`
+	ProfileName string       `json:"profile_name"`
+	Status      string       `json:"status"`
+	StartDate   time.Time    `json:"start_date"`
+	EndDate     time.Time    `json:"end_date"`
+	Findings    []FindingRef `json:"findings"`
+	SyncedAt    time.Time    `json:"synced_at"`
+}
+
+// Finding is the lifecycle of a vulnerability of a target across its scan results
+type Finding struct {
+	FindingRef
+	TargetID  string      `json:"target_id"`
+	FirstSeen time.Time   `json:"first_seen"`
+	LastSeen  time.Time   `json:"last_seen"`
+	Open      bool        `json:"open"`
+	FixedAt   []time.Time `json:"fixed_at,omitempty"`
+	Reopened  []time.Time `json:"reopened,omitempty"`
+	// Time from each detection to its fix
+	FixDurations []time.Duration `json:"fix_durations,omitempty"`
+}
+
+// ResultKey is the key of a snapshot, the results of a target sort by start date
+func ResultKey(targetID string, startDate time.Time, resultID string) string {
+	return targetID + "/" + startDate.UTC().Format(time.RFC3339) + "/" + resultID
+}
+
+// FindingKey is the key of a finding
+func FindingKey(targetID string, fingerprint string) string {
+	return targetID + "/" + fingerprint
+}
+
+// HasResult reports whether a result of a target is stored
+func (s *Store) HasResult(targetID string, resultID string) (bool, error) {
+	found := false
+	err := s.ForEach(BucketResults, targetID+"/", func(key string, data []byte) error {
+		if len(key) > len(resultID) && key[len(key)-len(resultID)-1:] == "/"+resultID {
+			found = true
+		}
+		return nil
+	})
+	return found, err
+}
+
+// Results returns the snapshots of a target, oldest first
+func (s *Store) Results(targetID string) ([]ResultSnapshot, error) {
+	var results []ResultSnapshot
+	err := s.ForEach(BucketResults, targetID+"/", func(key string, data []byte) error {
+		var result ResultSnapshot
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		results = append(results, result)
+		return nil
+	})
+	return results, err
+}
+
+// Findings returns the findings of a target
+func (s *Store) Findings(targetID string) ([]Finding, error) {
+	var findings []Finding
+	err := s.ForEach(BucketFindings, targetID+"/", func(key string, data []byte) error {
+		var finding Finding
+		if err := json.Unmarshal(data, &finding); err != nil {
+			return err
+		}
+		findings = append(findings, finding)
+		return nil
+	})
+	return findings, err
+}
+
+// Targets returns the stored targets
+func (s *Store) Targets() ([]TargetRecord, error) {
+	var targets []TargetRecord
+	err := s.ForEach(BucketTargets, "", func(key string, data []byte) error {
+		var target TargetRecord
+		if err := json.Unmarshal(data, &target); err != nil {
+			return err
+		}
+		targets = append(targets, target)
+		return nil
+	})
+	return targets, err
+}
+
+// RebuildFindings replays the completed results of a target in order and stores the lifecycle of every finding.
+// A finding missing from a later completed result of a scan profile which detected it before counts as fixed,
+// and as reopened when it is found again. Results of other profiles, e.g. a quick scan after a full scan,
+// do not fix the findings they do not look for.
+func (s *Store) RebuildFindings(targetID string) (int, error) {
+	results, err := s.Results(targetID)
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].StartDate.Before(results[j].StartDate) })
+
+	findings := make(map[string]*Finding)
+	detectedBy := make(map[string]map[string]bool)
+	for _, result := range results {
+		if result.Status != "completed" {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, ref := range result.Findings {
+			seen[ref.Fingerprint] = true
+			if detectedBy[ref.Fingerprint] == nil {
+				detectedBy[ref.Fingerprint] = make(map[string]bool)
+			}
+			detectedBy[ref.Fingerprint][result.ProfileName] = true
+			finding, ok := findings[ref.Fingerprint]
+			if !ok {
+				findings[ref.Fingerprint] = &Finding{FindingRef: ref, TargetID: targetID, FirstSeen: result.StartDate, LastSeen: result.StartDate, Open: true}
+				continue
+			}
+			if !finding.Open {
+				finding.Open = true
+				finding.Reopened = append(finding.Reopened, result.StartDate)
+			}
+			finding.FindingRef = ref
+			finding.LastSeen = result.StartDate
+		}
+
+		for fingerprint, finding := range findings {
+			if finding.Open && !seen[fingerprint] && detectedBy[fingerprint][result.ProfileName] {
+				finding.Open = false
+				finding.FixedAt = append(finding.FixedAt, result.StartDate)
+				detected := finding.FirstSeen
+				if len(finding.Reopened) > 0 {
+					detected = finding.Reopened[len(finding.Reopened)-1]
+				}
+				finding.FixDurations = append(finding.FixDurations, result.StartDate.Sub(detected))
+			}
+		}
+	}
+
+	values := make(map[string]interface{}, len(findings))
+	for fingerprint, finding := range findings {
+		values[FindingKey(targetID, fingerprint)] = finding
+	}
+	return len(findings), s.ReplacePrefix(BucketFindings, targetID+"/", values)
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the local database
+const (
+	BucketTargets  = "targets"
+	BucketResults  = "results"
+	BucketFindings = "findings"
+	BucketTickets  = "tickets"
+)
+
+// Store is the local database of acucli, a single bbolt file
+type Store struct {
+	db        *bolt.DB
+	closeOnce sync.Once
+}
+
+// bbolt locks the file for a single handle, callers in the same process (e.g. the jobs of acucli serve)
+// take turns instead of timing out on the file lock
+var openMu sync.Mutex
+
+// Path returns the path of the database, set store_path in the config to change it
+func Path() string {
+	if path := viper.GetString("store_path"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "acucli", "acucli.db")
+}
+
+// Open opens the database, creating it when needed. Only one process can have it open at a time,
+// within a process Open waits until the previous caller closed it.
+func Open() (*Store, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating store directory: %v", err)
+	}
+
+	openMu.Lock()
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		openMu.Unlock()
+		return nil, fmt.Errorf("error opening store %s: %v", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database and lets the next caller open it
+func (s *Store) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.db.Close()
+		openMu.Unlock()
+	})
+	return err
+}
+
+// Put stores a value as JSON
+func (s *Store) Put(bucket string, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Get reads a value, it reports false when the key does not exist
+func (s *Store) Get(bucket string, key string, value interface{}) (bool, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		if v := b.Get([]byte(key)); v != nil {
+			data = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, value)
+}
+
+// Delete removes a key
+func (s *Store) Delete(bucket string, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// ForEach calls fn with every key starting with prefix and its JSON value, in key order
+func (s *Store) ForEach(bucket string, prefix string, fn func(key string, data []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		p := []byte(prefix)
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			if err := fn(string(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReplacePrefix replaces every key starting with prefix by the given values in one transaction
+func (s *Store) ReplacePrefix(bucket string, prefix string, values map[string]interface{}) error {
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		encoded[key] = data
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		var old [][]byte
+		c := b.Cursor()
+		p := []byte(prefix)
+		for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+			old = append(old, append([]byte{}, k...))
+		}
+		for _, k := range old {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		for key, data := range encoded {
+			if err := b.Put([]byte(key), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,290 @@
+package vulnformat
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tosbaa/acucli/helpers/scandata"
+)
+
+// Output formats for vulnerability management tools
+const (
+	FormatJSON            = "json"
+	FormatDefectDojo      = "defectdojo"
+	FormatGenericFindings = "generic-findings"
+)
+
+// Formats lists the formats accepted by --format
+var Formats = []string{FormatJSON, FormatDefectDojo, FormatGenericFindings}
+
+// Valid reports whether a format is known
+func Valid(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Extension returns the file extension of a format
+func Extension(format string) string {
+	if format == FormatDefectDojo {
+		return ".xml"
+	}
+	return ".json"
+}
+
+// ScanType returns the DefectDojo scan type which imports a format
+func ScanType(format string) string {
+	if format == FormatDefectDojo {
+		return "Acunetix Scan"
+	}
+	return "Generic Findings Import"
+}
+
+// ScanFindings are the vulnerabilities of one target found by a scan
+type ScanFindings struct {
+	Address         string
+	StartDate       string
+	EndDate         string
+	Vulnerabilities []scandata.Vulnerability
+}
+
+// Encode writes the findings in a format other than json
+func Encode(format string, scans []ScanFindings) ([]byte, error) {
+	switch format {
+	case FormatDefectDojo:
+		return encodeAcunetixXML(scans)
+	case FormatGenericFindings:
+		return encodeGenericFindings(scans)
+	}
+	return nil, fmt.Errorf("unknown format %q (%s)", format, strings.Join(Formats, ", "))
+}
+
+// GroupByTarget splits vulnerabilities of several targets into one ScanFindings per target
+func GroupByTarget(vulnerabilities []scandata.Vulnerability, addresses map[string]string) []ScanFindings {
+	var scans []ScanFindings
+	index := make(map[string]int)
+	for _, v := range vulnerabilities {
+		i, ok := index[v.TargetID]
+		if !ok {
+			i = len(scans)
+			index[v.TargetID] = i
+			scans = append(scans, ScanFindings{Address: addresses[v.TargetID]})
+		}
+		scans[i].Vulnerabilities = append(scans[i].Vulnerabilities, v)
+	}
+	return scans
+}
+
+// Description of a finding: the vulnerability description followed by the details of this instance
+func description(v scandata.Vulnerability) string {
+	parts := []string{}
+	for _, text := range []string{scandata.PlainText(v.Description), scandata.PlainText(v.Details)} {
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) == 0 {
+		return v.Name
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Severity names of the Acunetix XML export: high, medium, low and informational. The format has no
+// critical level, critical findings are written as high (use generic-findings to keep them critical).
+func xmlSeverity(severity int) string {
+	switch severity {
+	case scandata.SeverityInfo:
+		return "informational"
+	case scandata.SeverityCritical:
+		return scandata.SeverityName(scandata.SeverityHigh)
+	}
+	return scandata.SeverityName(severity)
+}
+
+// Dates in the Acunetix XML export are written day first
+func xmlDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ""
+	}
+	return t.Format("02/01/2006, 15:04:05")
+}
+
+type xmlScanGroup struct {
+	XMLName    xml.Name  `xml:"ScanGroup"`
+	ExportedOn string    `xml:"ExportedOn,attr"`
+	Scans      []xmlScan `xml:"Scan"`
+}
+
+type xmlScan struct {
+	Name        string          `xml:"Name"`
+	ShortName   string          `xml:"ShortName"`
+	StartURL    string          `xml:"StartURL"`
+	StartTime   string          `xml:"StartTime"`
+	FinishTime  string          `xml:"FinishTime"`
+	ReportItems []xmlReportItem `xml:"ReportItems>ReportItem"`
+}
+
+type xmlReportItem struct {
+	ID               string         `xml:"id,attr"`
+	Name             string         `xml:"Name"`
+	ModuleName       string         `xml:"ModuleName"`
+	Details          string         `xml:"Details"`
+	Affects          string         `xml:"Affects"`
+	Parameter        string         `xml:"Parameter"`
+	IsFalsePositive  string         `xml:"IsFalsePositive"`
+	Severity         string         `xml:"Severity"`
+	Type             string         `xml:"Type"`
+	Impact           string         `xml:"Impact"`
+	Description      string         `xml:"Description"`
+	Recommendation   string         `xml:"Recommendation"`
+	TechnicalDetails xmlTechnical   `xml:"TechnicalDetails"`
+	CWEList          []xmlCWE       `xml:"CWEList>CWE"`
+	References       []xmlReference `xml:"References>Reference"`
+}
+
+type xmlTechnical struct {
+	Request  string `xml:"Request"`
+	Response string `xml:"Response"`
+}
+
+type xmlCWE struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlReference struct {
+	Database string `xml:"Database"`
+	URL      string `xml:"URL"`
+}
+
+// The XML export of Acunetix, imported by DefectDojo as "Acunetix Scan"
+func encodeAcunetixXML(scans []ScanFindings) ([]byte, error) {
+	group := xmlScanGroup{ExportedOn: time.Now().Format("02/01/2006, 15:04:05")}
+	for _, scan := range scans {
+		x := xmlScan{
+			Name:       scan.Address,
+			ShortName:  scan.Address,
+			StartURL:   scan.Address,
+			StartTime:  xmlDate(scan.StartDate),
+			FinishTime: xmlDate(scan.EndDate),
+		}
+		for i, v := range scan.Vulnerabilities {
+			item := xmlReportItem{
+				ID:               strconv.Itoa(i),
+				Name:             v.Name,
+				ModuleName:       v.VtID,
+				Details:          scandata.PlainText(v.Details),
+				Affects:          v.AffectsURL,
+				Parameter:        v.AffectsDetail,
+				IsFalsePositive:  "False",
+				Severity:         xmlSeverity(v.Severity),
+				Type:             v.VtID,
+				Impact:           scandata.PlainText(v.Impact),
+				Description:      scandata.PlainText(v.Description),
+				Recommendation:   scandata.PlainText(v.Recommendation),
+				TechnicalDetails: xmlTechnical{Request: v.Request},
+			}
+			if cwe := v.CWE(); cwe != 0 {
+				item.CWEList = []xmlCWE{{ID: strconv.Itoa(cwe), Value: fmt.Sprintf("CWE-%d", cwe)}}
+			}
+			for _, ref := range v.References {
+				item.References = append(item.References, xmlReference{Database: ref.Rel, URL: ref.Href})
+			}
+			x.ReportItems = append(x.ReportItems, item)
+		}
+		group.Scans = append(group.Scans, x)
+	}
+
+	data, err := xml.MarshalIndent(group, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+type genericEndpoint struct {
+	Protocol string `json:"protocol,omitempty"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Query    string `json:"query,omitempty"`
+}
+
+type genericFinding struct {
+	Title            string            `json:"title"`
+	Description      string            `json:"description"`
+	Severity         string            `json:"severity"`
+	Mitigation       string            `json:"mitigation,omitempty"`
+	Impact           string            `json:"impact,omitempty"`
+	References       string            `json:"references,omitempty"`
+	Date             string            `json:"date,omitempty"`
+	CWE              int               `json:"cwe,omitempty"`
+	Param            string            `json:"param,omitempty"`
+	VulnIDFromTool   string            `json:"vuln_id_from_tool,omitempty"`
+	UniqueIDFromTool string            `json:"unique_id_from_tool"`
+	StaticFinding    bool              `json:"static_finding"`
+	DynamicFinding   bool              `json:"dynamic_finding"`
+	Endpoints        []genericEndpoint `json:"endpoints,omitempty"`
+}
+
+// Endpoint of an affected URL, or nil when it cannot be parsed
+func endpointOf(address string) *genericEndpoint {
+	u, err := url.Parse(address)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	endpoint := genericEndpoint{Protocol: u.Scheme, Host: u.Hostname(), Path: strings.TrimPrefix(u.Path, "/"), Query: u.RawQuery}
+	if port, err := strconv.Atoi(u.Port()); err == nil {
+		endpoint.Port = port
+	}
+	return &endpoint
+}
+
+// The Generic Findings Import JSON of DefectDojo
+func encodeGenericFindings(scans []ScanFindings) ([]byte, error) {
+	findings := []genericFinding{}
+	for _, scan := range scans {
+		for _, v := range scan.Vulnerabilities {
+			severity := scandata.SeverityName(v.Severity)
+			finding := genericFinding{
+				Title:            v.Name,
+				Description:      description(v),
+				Severity:         strings.ToUpper(severity[:1]) + severity[1:],
+				Mitigation:       scandata.PlainText(v.Recommendation),
+				Impact:           scandata.PlainText(v.Impact),
+				CWE:              v.CWE(),
+				Param:            v.AffectsDetail,
+				VulnIDFromTool:   v.VtID,
+				UniqueIDFromTool: v.Fingerprint(v.TargetID),
+				DynamicFinding:   true,
+			}
+			if t, err := time.Parse(time.RFC3339, v.LastSeen); err == nil {
+				finding.Date = t.Format("2006-01-02")
+			}
+			var refs []string
+			for _, ref := range v.References {
+				refs = append(refs, fmt.Sprintf("%s: %s", ref.Rel, ref.Href))
+			}
+			finding.References = strings.Join(refs, "\n")
+			if endpoint := endpointOf(v.AffectsURL); endpoint != nil {
+				finding.Endpoints = []genericEndpoint{*endpoint}
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"findings": findings}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}